@@ -1,12 +1,13 @@
-package main
+package day1
 
 import (
 	"bufio"
-	"fmt"
 	"log"
 	"os"
 	"regexp"
 	"strconv"
+
+	"github.com/nsanch/aoc/aoc2023/utils"
 )
 
 func part1(fname string) int {
@@ -77,10 +78,6 @@ func part2(fname string) int {
 	return total
 }
 
-func main() {
-	fmt.Println(part1("day1/day1-input-easy.txt"))
-	fmt.Println(part1("day1/day1-input.txt"))
-
-	fmt.Println(part2("day1/day1-input-easy2.txt"))
-	fmt.Println(part2("day1/day1-input.txt"))
+func init() {
+	utils.RegisterPuzzle(2023, 1, part1, part2)
 }
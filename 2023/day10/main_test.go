@@ -0,0 +1,21 @@
+package day10
+
+import (
+	"testing"
+
+	"github.com/nsanch/aoc/aoc2023/utils"
+)
+
+func TestInteriorPointsMatchesPolygon(t *testing.T) {
+	for _, fname := range []string{"day10-input-easy3.txt", "day10-input-easy4.txt", "day10-input.txt"} {
+		grid := GridWithPipes{utils.MustReadGridFromFile(fname)}
+		graph := MakeGraphFromGrid(grid)
+		path, err := graph.FindUndirectedCycleThrough(grid.FindStartingPosition())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := utils.GetInteriorPoints(path).NumPoints(), part2(fname); got != want {
+			t.Errorf("GetInteriorPoints(%s) = %v, want %v", fname, got, want)
+		}
+	}
+}
@@ -1,13 +1,12 @@
-package main
+package day11
 
 import (
-	"fmt"
 	"slices"
 
 	"github.com/nsanch/aoc/aoc2023/utils"
 )
 
-func getRowsWithoutGalaxies(grid utils.Grid) map[int]bool {
+func getRowsWithoutGalaxies(grid utils.Grid[rune]) map[int]bool {
 	var ret []int
 	for y, row := range grid {
 		if !slices.Contains(row, '#') {
@@ -17,7 +16,7 @@ func getRowsWithoutGalaxies(grid utils.Grid) map[int]bool {
 	return utils.MakeSetFromSlice(ret)
 }
 
-func getColumnsWithoutGalaxies(grid utils.Grid) map[int]bool {
+func getColumnsWithoutGalaxies(grid utils.Grid[rune]) map[int]bool {
 	var ret []int
 	for x := 0; x < len(grid[0]); x++ {
 		colHasGalaxy := false
@@ -33,9 +32,9 @@ func getColumnsWithoutGalaxies(grid utils.Grid) map[int]bool {
 	return utils.MakeSetFromSlice(ret)
 }
 
-func parseFilePart1(fname string) utils.Grid {
-	grid := utils.ReadGridFromFile(fname)
-	gridWithDoubledRows := make(utils.Grid, 0)
+func parseFilePart1(fname string) utils.Grid[rune] {
+	grid := utils.MustReadGridFromFile(fname)
+	gridWithDoubledRows := make(utils.Grid[rune], 0)
 	for _, row := range grid {
 		rowHasGalaxy := false
 		for _, r := range row {
@@ -52,7 +51,7 @@ func parseFilePart1(fname string) utils.Grid {
 		}
 	}
 
-	gridWithDoubledColumns := make(utils.Grid, len(gridWithDoubledRows))
+	gridWithDoubledColumns := make(utils.Grid[rune], len(gridWithDoubledRows))
 	for x := 0; x < len(gridWithDoubledRows[0]); x++ {
 		colHasGalaxy := false
 		for y := 0; y < len(gridWithDoubledRows); y++ {
@@ -71,7 +70,7 @@ func parseFilePart1(fname string) utils.Grid {
 	return gridWithDoubledColumns
 }
 
-func findGalaxies(grid utils.Grid) []utils.Position {
+func findGalaxies(grid utils.Grid[rune]) []utils.Position {
 	var galaxies []utils.Position
 	for y, row := range grid {
 		for x, r := range row {
@@ -110,7 +109,7 @@ func countDoubledThingsInRange(doubledRows map[int]bool, start int, end int) int
 }
 
 func part2(fname string, scalingFactor int) int {
-	grid := utils.ReadGridFromFile(fname)
+	grid := utils.MustReadGridFromFile(fname)
 	galaxies := findGalaxies(grid)
 	doubledRows := getRowsWithoutGalaxies(grid)
 	doubledColumns := getColumnsWithoutGalaxies(grid)
@@ -126,12 +125,6 @@ func part2(fname string, scalingFactor int) int {
 	return results
 }
 
-func main() {
-	fmt.Println(part1("day11-input-easy.txt"))
-	fmt.Println(part1("day11-input.txt"))
-
-	fmt.Println(part2("day11-input-easy.txt", 2))
-	fmt.Println(part2("day11-input-easy.txt", 10))
-	fmt.Println(part2("day11-input-easy.txt", 100))
-	fmt.Println(part2("day11-input.txt", 1000000))
+func init() {
+	utils.RegisterPuzzle(2023, 11, part1, func(fname string) int { return part2(fname, 1000000) })
 }
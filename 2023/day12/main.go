@@ -1,9 +1,8 @@
-package main
+package day12
 
 import (
 	"bufio"
 	"cmp"
-	"fmt"
 	"log"
 	"os"
 	"slices"
@@ -44,7 +43,7 @@ func parseFile(fname string) []Row {
 		row := scanner.Text()
 		splitUp := strings.Split(row, " ")
 		springs := splitUp[0]
-		sizesOfBrokenSets := utils.ConvertStringsToInts(strings.Split(splitUp[1], ","))
+		sizesOfBrokenSets := utils.MustConvertStringsToInts(strings.Split(splitUp[1], ","))
 		ret = append(ret, Row{springs: springs, sizesOfBrokenSets: sizesOfBrokenSets})
 	}
 	return ret
@@ -268,10 +267,6 @@ func part2(fname string) int {
 	return result
 }
 
-func main() {
-	fmt.Println(part1("day12-input-easy.txt"))
-	fmt.Println(part1("day12-input.txt"))
-
-	fmt.Println(part2("day12-input-easy.txt"))
-	fmt.Println(part2("day12-input.txt"))
+func init() {
+	utils.RegisterPuzzle(2023, 12, part1, part2)
 }
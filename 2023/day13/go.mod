@@ -1,4 +1,4 @@
-module github.com/nsanch/aoc/aoc2023/day12
+module github.com/nsanch/aoc/aoc2023/day13
 
 go 1.24.1
 
@@ -1,14 +1,13 @@
-package main
+package day13
 
 import (
-	"fmt"
 	"log"
 	"slices"
 
 	"github.com/nsanch/aoc/aoc2023/utils"
 )
 
-func findAllPositionsOfReflectingRows(grid utils.Grid) []int {
+func findAllPositionsOfReflectingRows(grid utils.Grid[rune]) []int {
 	var ret []int
 	for y := 1; y < len(grid); y++ {
 		// we have a reflecting row, back up and see how many more we can find.
@@ -26,7 +25,7 @@ func findAllPositionsOfReflectingRows(grid utils.Grid) []int {
 }
 
 func part1(fname string) int {
-	grids := utils.ReadGridsFromFile(fname)
+	grids := utils.MustReadGridsFromFile(fname)
 	result := 0
 	for _, grid := range grids {
 		rowPositions := findAllPositionsOfReflectingRows(grid)
@@ -63,7 +62,7 @@ func fixExactlyOneDifference(a, b []rune) (bool, []rune, int) {
 	}
 }
 
-func findPositionOfReflectingRowsWithASmudge(grid utils.Grid) int {
+func findPositionOfReflectingRowsWithASmudge(grid utils.Grid[rune]) int {
 	oldReflections := utils.MakeSetFromSlice(findAllPositionsOfReflectingRows(grid))
 	allNewIndexes := make([]int, 0)
 	for y := range grid {
@@ -98,7 +97,7 @@ func findPositionOfReflectingRowsWithASmudge(grid utils.Grid) int {
 }
 
 func part2(fname string) int {
-	grids := utils.ReadGridsFromFile(fname)
+	grids := utils.MustReadGridsFromFile(fname)
 	result := 0
 	for _, grid := range grids {
 		result += 100 * findPositionOfReflectingRowsWithASmudge(grid)
@@ -108,10 +107,6 @@ func part2(fname string) int {
 	return result
 }
 
-func main() {
-	fmt.Println(part1("day13-input-easy.txt"))
-	fmt.Println(part1("day13-input.txt"))
-
-	fmt.Println(part2("day13-input-easy.txt"))
-	fmt.Println(part2("day13-input.txt"))
+func init() {
+	utils.RegisterPuzzle(2023, 13, part1, part2)
 }
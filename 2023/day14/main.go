@@ -1,13 +1,12 @@
-package main
+package day14
 
 import (
-	"fmt"
 	"slices"
 
 	"github.com/nsanch/aoc/aoc2023/utils"
 )
 
-func getIterationOrder(grid utils.Grid, d utils.Direction) []utils.Position {
+func getIterationOrder(grid utils.Grid[rune], d utils.Direction) []utils.Position {
 	iterationOrder := make([]utils.Position, 0)
 	switch d {
 	case utils.North:
@@ -35,7 +34,7 @@ func getIterationOrder(grid utils.Grid, d utils.Direction) []utils.Position {
 	return iterationOrder
 }
 
-func tiltGrid(grid utils.Grid, d utils.Direction) bool {
+func tiltGrid(grid utils.Grid[rune], d utils.Direction) bool {
 	iterationOrder := getIterationOrder(grid, d)
 
 	tiltedAnything := false
@@ -55,7 +54,7 @@ func tiltGrid(grid utils.Grid, d utils.Direction) bool {
 	return tiltedAnything
 }
 
-func ScoreGrid(grid utils.Grid) int {
+func ScoreGrid(grid utils.Grid[rune]) int {
 	result := 0
 	for y, row := range grid {
 		for _, r := range row {
@@ -68,13 +67,13 @@ func ScoreGrid(grid utils.Grid) int {
 }
 
 func part1(fname string) int {
-	grid := utils.ReadGridFromFile(fname)
+	grid := utils.MustReadGridFromFile(fname)
 	tiltGrid(grid, utils.North)
 	result := ScoreGrid(grid)
 	return result
 }
 
-func spinGrid(grid utils.Grid) {
+func spinGrid(grid utils.Grid[rune]) {
 	tiltGrid(grid, utils.North)
 	tiltGrid(grid, utils.West)
 	tiltGrid(grid, utils.South)
@@ -104,9 +103,8 @@ func lookForRepeatingPattern(input []int) ([]int, int) {
 }
 
 func part2(fname string) int {
-	grid := utils.ReadGridFromFile(fname)
+	grid := utils.MustReadGridFromFile(fname)
 	//fmt.Println(grid.String())
-	fmt.Println()
 
 	spinGrid(grid)
 
@@ -120,14 +118,10 @@ func part2(fname string) int {
 	}
 	pattern, offset := lookForRepeatingPattern(allPriorScores)
 	result := pattern[(1000000000-offset-1)%len(pattern)]
-	fmt.Printf("Found repeating pattern of %v starting at offset %d\n", pattern, offset)
+	//fmt.Printf("Found repeating pattern of %v starting at offset %d\n", pattern, offset)
 	return result
 }
 
-func main() {
-	fmt.Println(part1("day14-input-easy.txt"))
-	fmt.Println(part1("day14-input.txt"))
-
-	fmt.Println(part2("day14-input-easy.txt"))
-	fmt.Println(part2("day14-input.txt"))
+func init() {
+	utils.RegisterPuzzle(2023, 14, part1, part2)
 }
@@ -1,13 +1,14 @@
-package main
+package day15
 
 import (
 	"bufio"
-	"fmt"
 	"os"
 	"regexp"
 	"slices"
 	"strconv"
 	"strings"
+
+	"github.com/nsanch/aoc/aoc2023/utils"
 )
 
 func hash(s string) int {
@@ -119,10 +120,6 @@ func part2(fname string) int {
 	return boxes.Score()
 }
 
-func main() {
-	fmt.Println(part1("day15-input-easy.txt"))
-	fmt.Println(part1("day15-input.txt"))
-
-	fmt.Println(part2("day15-input-easy.txt"))
-	fmt.Println(part2("day15-input.txt"))
+func init() {
+	utils.RegisterPuzzle(2023, 15, part1, part2)
 }
@@ -1,9 +1,6 @@
-package main
+package day16
 
 import (
-	"fmt"
-	"slices"
-
 	"github.com/nsanch/aoc/aoc2023/utils"
 )
 
@@ -23,85 +20,59 @@ func (m *VisitedMap) Visit(pos utils.Position, direction utils.Direction) {
 	(*m)[VisitedKey{Direction: direction, Position: pos}] = true
 }
 
-func simulateLight(grid utils.Grid, energyGrid utils.Grid, lightPos utils.Position, lightDirection utils.Direction, visited *VisitedMap) {
+// tileTurns says which way(s) light leaves each kind of tile, given the way it was heading
+// when it entered. anything not in the table lets light pass straight through.
+var tileTurns = map[rune]func(utils.Direction) []utils.Direction{
+	'/':  func(d utils.Direction) []utils.Direction { return []utils.Direction{d.ReflectSlash()} },
+	'\\': func(d utils.Direction) []utils.Direction { return []utils.Direction{d.ReflectBackslash()} },
+	'|': func(d utils.Direction) []utils.Direction {
+		if d == utils.East || d == utils.West {
+			return []utils.Direction{d.TurnLeft(), d.TurnRight()}
+		}
+		return []utils.Direction{d}
+	},
+	'-': func(d utils.Direction) []utils.Direction {
+		if d == utils.North || d == utils.South {
+			return []utils.Direction{d.TurnLeft(), d.TurnRight()}
+		}
+		return []utils.Direction{d}
+	},
+}
+
+func simulateLight(grid utils.Grid[rune], energyGrid utils.Grid[bool], lightPos utils.Position, lightDirection utils.Direction, visited *VisitedMap) {
 	if visited.DidVisit(lightPos, lightDirection) {
 		return
 	}
 	(*visited).Visit(lightPos, lightDirection)
 
 	//fmt.Printf("lightPos: %v, lightDirection: %v. item: %c\n", lightPos, lightDirection, grid.ItemAt(lightPos))
-	energyGrid.Set(lightPos, '#')
-	switch grid.ItemAt(lightPos) {
-	case '/':
-		switch lightDirection {
-		case utils.North:
-			lightDirection = utils.East
-		case utils.East:
-			lightDirection = utils.North
-		case utils.South:
-			lightDirection = utils.West
-		case utils.West:
-			lightDirection = utils.South
-		}
-	case '\\':
-		switch lightDirection {
-		case utils.North:
-			lightDirection = utils.West
-		case utils.West:
-			lightDirection = utils.North
-		case utils.South:
-			lightDirection = utils.East
-		case utils.East:
-			lightDirection = utils.South
-		}
-	case '|':
-		if lightDirection == utils.East || lightDirection == utils.West {
-			simulateLight(grid, energyGrid, lightPos, utils.South, visited)
-			simulateLight(grid, energyGrid, lightPos, utils.North, visited)
-			return
-		}
-	case '-':
-		if lightDirection == utils.South || lightDirection == utils.North {
-			simulateLight(grid, energyGrid, lightPos, utils.West, visited)
-			simulateLight(grid, energyGrid, lightPos, utils.East, visited)
-			return
-		}
-	}
-	hasNext, next := lightPos.FollowDirection(lightDirection, len(grid[0])-1, len(grid)-1)
-	if hasNext {
-		simulateLight(grid, energyGrid, next, lightDirection, visited)
+	energyGrid.Set(lightPos, true)
+	nextDirections := []utils.Direction{lightDirection}
+	if turn, ok := tileTurns[grid.ItemAt(lightPos)]; ok {
+		nextDirections = turn(lightDirection)
 	}
-}
-
-func ScoreGrid(grid utils.Grid) int {
-	score := 0
-	for y := range grid {
-		for x := range grid[y] {
-			if grid[y][x] == '#' {
-				score++
-			}
+	for _, nextDirection := range nextDirections {
+		hasNext, next := lightPos.FollowDirection(nextDirection, len(grid[0])-1, len(grid)-1)
+		if hasNext {
+			simulateLight(grid, energyGrid, next, nextDirection, visited)
 		}
 	}
-	return score
 }
 
-func SimulateLightForGrid(grid utils.Grid, startingPos utils.Position, startingDirection utils.Direction) int {
-	energyGrid := make(utils.Grid, len(grid))
-	for i := range energyGrid {
-		energyGrid[i] = slices.Repeat([]rune{'.'}, len(grid[i]))
-	}
+func SimulateLightForGrid(grid utils.Grid[rune], startingPos utils.Position, startingDirection utils.Direction) int {
+	energyGrid := utils.NewGrid(grid.Width(), grid.Height(), false)
 	visitedMap := make(VisitedMap)
 	simulateLight(grid, energyGrid, startingPos, startingDirection, &visitedMap)
-	return ScoreGrid(energyGrid)
+	return energyGrid.Count(true)
 }
 
 func part1(fname string) int {
-	grid := utils.ReadGridFromFile(fname)
+	grid := utils.MustReadGridFromFile(fname)
 	return SimulateLightForGrid(grid, utils.Position{Y: 0, X: 0}, utils.East)
 }
 
 func part2(fname string) int {
-	grid := utils.ReadGridFromFile(fname)
+	grid := utils.MustReadGridFromFile(fname)
 	bestScore := 0
 	type Start struct {
 		pos       utils.Position
@@ -125,10 +96,6 @@ func part2(fname string) int {
 	return bestScore
 }
 
-func main() {
-	fmt.Println(part1("day16-input-easy.txt"))
-	fmt.Println(part1("day16-input.txt"))
-
-	fmt.Println(part2("day16-input-easy.txt"))
-	fmt.Println(part2("day16-input.txt"))
+func init() {
+	utils.RegisterPuzzle(2023, 16, part1, part2)
 }
@@ -1,7 +1,8 @@
-package main
+package day17
 
 import (
-	"fmt"
+	"iter"
+	"log"
 
 	"github.com/nsanch/aoc/aoc2023/utils"
 )
@@ -12,161 +13,79 @@ type GraphKey struct {
 	direction utils.Direction
 }
 
-type Day17Graph = utils.Graph[GraphKey]
+// A Crucible has to move at least MinStraight blocks in a direction before it can
+// turn, and can't move more than MaxStraight blocks without turning.
+type Crucible struct {
+	MinStraight int
+	MaxStraight int
+}
 
-func makeGridIntoGraph(grid utils.Grid) Day17Graph {
-	graph := make(Day17Graph)
-	for y, row := range grid {
-		for x := range row {
-			from := utils.Position{X: x, Y: y}
-			hasNorth, north := from.North()
-			hasEast, east := from.East(len(row) - 1)
-			hasWest, west := from.West()
-			hasSouth, south := from.South(len(grid) - 1)
-			for level := 0; level <= 3; level++ {
-				fromEast := GraphKey{position: from, direction: utils.East, level: level}
-				fromWest := GraphKey{position: from, direction: utils.West, level: level}
-				fromNorth := GraphKey{position: from, direction: utils.North, level: level}
-				fromSouth := GraphKey{position: from, direction: utils.South, level: level}
-				if hasNorth {
-					if level < 3 {
-						graph.AddEdge(fromNorth, GraphKey{position: north, direction: utils.North, level: level + 1}, int(grid.ItemAt(north)-'0'))
+func (c Crucible) neighbors(grid utils.Grid[int]) func(GraphKey) iter.Seq[utils.Neighbor[GraphKey]] {
+	return func(from GraphKey) iter.Seq[utils.Neighbor[GraphKey]] {
+		return func(yield func(utils.Neighbor[GraphKey]) bool) {
+			for direction := range utils.AllDirections() {
+				level := 1
+				if direction == from.direction {
+					if from.level >= c.MaxStraight {
+						continue
 					}
-					graph.AddEdge(fromEast, GraphKey{position: north, direction: utils.North, level: 1}, int(grid.ItemAt(north)-'0'))
-					graph.AddEdge(fromWest, GraphKey{position: north, direction: utils.North, level: 1}, int(grid.ItemAt(north)-'0'))
+					level = from.level + 1
+				} else if direction == from.direction.MustReverse() || from.level < c.MinStraight {
+					continue
 				}
-				if hasSouth {
-					if level < 3 {
-						graph.AddEdge(fromSouth, GraphKey{position: south, direction: utils.South, level: level + 1}, int(grid.ItemAt(south)-'0'))
-					}
-					graph.AddEdge(fromEast, GraphKey{position: south, direction: utils.South, level: 1}, int(grid.ItemAt(south)-'0'))
-					graph.AddEdge(fromWest, GraphKey{position: south, direction: utils.South, level: 1}, int(grid.ItemAt(south)-'0'))
+				next := from.position.Step(direction, 1)
+				if !grid.InBounds(next) {
+					continue
 				}
-				if hasEast {
-					if level < 3 {
-						graph.AddEdge(fromEast, GraphKey{position: east, direction: utils.East, level: level + 1}, int(grid.ItemAt(east)-'0'))
-					}
-					graph.AddEdge(fromSouth, GraphKey{position: east, direction: utils.East, level: 1}, int(grid.ItemAt(east)-'0'))
-					graph.AddEdge(fromNorth, GraphKey{position: east, direction: utils.East, level: 1}, int(grid.ItemAt(east)-'0'))
-				}
-				if hasWest {
-					if level < 3 {
-						graph.AddEdge(fromWest, GraphKey{position: west, direction: utils.West, level: level + 1}, int(grid.ItemAt(west)-'0'))
-					}
-					graph.AddEdge(fromSouth, GraphKey{position: west, direction: utils.West, level: 1}, int(grid.ItemAt(west)-'0'))
-					graph.AddEdge(fromNorth, GraphKey{position: west, direction: utils.West, level: 1}, int(grid.ItemAt(west)-'0'))
+				if !yield(utils.Neighbor[GraphKey]{Value: GraphKey{position: next, level: level, direction: direction}, Cost: grid.ItemAt(next)}) {
+					return
 				}
 			}
 		}
 	}
-	return graph
 }
 
-func part1(fname string) int {
-	grid := utils.ReadGridFromFile(fname)
-	graph := makeGridIntoGraph(grid)
-	directions := []utils.Direction{utils.North, utils.East, utils.West, utils.South}
+// minimumHeatLoss returns the least heat lost getting from the top-left to the bottom-right
+// corner, and how many states the search expanded. with useHeuristic it runs A*, using the
+// manhattan distance to the corner since every block loses at least 1 heat.
+func (c Crucible) minimumHeatLoss(grid utils.Grid[int], useHeuristic bool) (int, int) {
 	froms := make([]GraphKey, 0)
-	for _, direction := range directions {
+	// level 0 means the crucible hasn't moved yet, so it can head off in any direction.
+	for direction := range utils.AllDirections() {
 		froms = append(froms, GraphKey{position: utils.Position{X: 0, Y: 0}, level: 0, direction: direction})
 	}
-	ends := make([]GraphKey, 0)
-	for level := 0; level <= 3; level++ {
-		for _, direction := range directions {
-			ends = append(ends, GraphKey{position: utils.Position{X: len(grid[0]) - 1, Y: len(grid) - 1}, level: level, direction: direction})
+	end := utils.Position{X: grid.Width() - 1, Y: grid.Height() - 1}
+	isGoal := func(k GraphKey) bool {
+		return k.position == end && k.level >= c.MinStraight
+	}
+	var heuristic func(GraphKey) int
+	if useHeuristic {
+		heuristic = func(k GraphKey) int {
+			return k.position.ManhattanDistance(end)
 		}
 	}
-	distance, _ := graph.FindDistanceAndPath(froms, ends)
+	distance, _, expanded, err := utils.FindDistanceAndPathAStarFunc(froms, c.neighbors(grid), isGoal, heuristic)
+	if err != nil {
+		log.Fatal(err)
+	}
 	/*for path := range paths {
 		fmt.Println(paths[path])
-		//		fmt.Println(renderPath(grid.Clone(), paths[path]))
 	}*/
-	return distance
+	return distance, expanded
 }
 
-func makeGridIntoGraphPart2(grid utils.Grid) Day17Graph {
-	graph := make(Day17Graph)
-	for y, row := range grid {
-		for x := range row {
-			from := utils.Position{X: x, Y: y}
-			hasNorth, north := from.North()
-			hasEast, east := from.East(len(row) - 1)
-			hasWest, west := from.West()
-			hasSouth, south := from.South(len(grid) - 1)
-			for level := 0; level <= 10; level++ {
-				fromEast := GraphKey{position: from, direction: utils.East, level: level}
-				fromWest := GraphKey{position: from, direction: utils.West, level: level}
-				fromNorth := GraphKey{position: from, direction: utils.North, level: level}
-				fromSouth := GraphKey{position: from, direction: utils.South, level: level}
-				if hasNorth {
-					if level < 10 {
-						graph.AddEdge(fromNorth, GraphKey{position: north, direction: utils.North, level: level + 1}, int(grid.ItemAt(north)-'0'))
-					}
-					if level >= 4 {
-						graph.AddEdge(fromEast, GraphKey{position: north, direction: utils.North, level: 1}, int(grid.ItemAt(north)-'0'))
-						graph.AddEdge(fromWest, GraphKey{position: north, direction: utils.North, level: 1}, int(grid.ItemAt(north)-'0'))
-					}
-				}
-				if hasSouth {
-					if level < 10 {
-						graph.AddEdge(fromSouth, GraphKey{position: south, direction: utils.South, level: level + 1}, int(grid.ItemAt(south)-'0'))
-					}
-					if level >= 4 {
-						graph.AddEdge(fromEast, GraphKey{position: south, direction: utils.South, level: 1}, int(grid.ItemAt(south)-'0'))
-						graph.AddEdge(fromWest, GraphKey{position: south, direction: utils.South, level: 1}, int(grid.ItemAt(south)-'0'))
-					}
-				}
-				if hasEast {
-					if level < 10 {
-						graph.AddEdge(fromEast, GraphKey{position: east, direction: utils.East, level: level + 1}, int(grid.ItemAt(east)-'0'))
-					}
-					if level >= 4 {
-						graph.AddEdge(fromSouth, GraphKey{position: east, direction: utils.East, level: 1}, int(grid.ItemAt(east)-'0'))
-						graph.AddEdge(fromNorth, GraphKey{position: east, direction: utils.East, level: 1}, int(grid.ItemAt(east)-'0'))
-					}
-				}
-				if hasWest {
-					if level < 10 {
-						graph.AddEdge(fromWest, GraphKey{position: west, direction: utils.West, level: level + 1}, int(grid.ItemAt(west)-'0'))
-					}
-					if level >= 4 {
-						graph.AddEdge(fromSouth, GraphKey{position: west, direction: utils.West, level: 1}, int(grid.ItemAt(west)-'0'))
-						graph.AddEdge(fromNorth, GraphKey{position: west, direction: utils.West, level: 1}, int(grid.ItemAt(west)-'0'))
-					}
-				}
-			}
-		}
-	}
-	return graph
+func part1(fname string) int {
+	grid := utils.MustReadGridFromFileAs(fname, utils.ParseDigit)
+	distance, _ := Crucible{MinStraight: 1, MaxStraight: 3}.minimumHeatLoss(grid, true)
+	return distance
 }
 
 func part2(fname string) int {
-	grid := utils.ReadGridFromFile(fname)
-	graph := makeGridIntoGraphPart2(grid)
-	directions := []utils.Direction{utils.North, utils.East, utils.West, utils.South}
-	froms := make([]GraphKey, 0)
-	for _, direction := range directions {
-		froms = append(froms, GraphKey{position: utils.Position{X: 0, Y: 0}, level: 0, direction: direction})
-	}
-	ends := make([]GraphKey, 0)
-	for level := 4; level <= 10; level++ {
-		for _, direction := range directions {
-			ends = append(ends, GraphKey{position: utils.Position{X: len(grid[0]) - 1, Y: len(grid) - 1}, level: level, direction: direction})
-		}
-	}
-	distance, _ := graph.FindDistanceAndPath(froms, ends)
-	/*for path := range paths {
-		fmt.Println(paths[path])
-	}*/
+	grid := utils.MustReadGridFromFileAs(fname, utils.ParseDigit)
+	distance, _ := Crucible{MinStraight: 4, MaxStraight: 10}.minimumHeatLoss(grid, true)
 	return distance
 }
 
-func main() {
-	fmt.Println(part1("day17-input-easy2.txt"))
-	fmt.Println(part1("day17-input-easy.txt"))
-	fmt.Println(part1("day17-input.txt"))
-
-	fmt.Println(part2("day17-input-easy3.txt"))
-	fmt.Println(part2("day17-input-easy.txt"))
-	fmt.Println(part2("day17-input.txt"))
+func init() {
+	utils.RegisterPuzzle(2023, 17, part1, part2)
 }
@@ -0,0 +1,51 @@
+package day17
+
+import (
+	"testing"
+
+	"github.com/nsanch/aoc/aoc2023/utils"
+)
+
+func TestMinimumHeatLossAStar(t *testing.T) {
+	tests := []struct {
+		fname    string
+		crucible Crucible
+		want     int
+	}{
+		{"day17-input-easy.txt", Crucible{MinStraight: 1, MaxStraight: 3}, 102},
+		{"day17-input-easy.txt", Crucible{MinStraight: 4, MaxStraight: 10}, 94},
+		{"day17-input-easy3.txt", Crucible{MinStraight: 4, MaxStraight: 10}, 71},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fname, func(t *testing.T) {
+			grid := utils.MustReadGridFromFileAs(tt.fname, utils.ParseDigit)
+			dijkstra, dijkstraExpanded := tt.crucible.minimumHeatLoss(grid, false)
+			astar, astarExpanded := tt.crucible.minimumHeatLoss(grid, true)
+			if dijkstra != tt.want || astar != tt.want {
+				t.Errorf("minimumHeatLoss() = %v (dijkstra), %v (A*), want %v", dijkstra, astar, tt.want)
+			}
+			if astarExpanded > dijkstraExpanded {
+				t.Errorf("A* expanded %d states, more than dijkstra's %d", astarExpanded, dijkstraExpanded)
+			}
+			t.Logf("dijkstra expanded %d states, A* expanded %d", dijkstraExpanded, astarExpanded)
+		})
+	}
+}
+
+// go test -bench . -benchtime 1x runs these once each, which is enough to compare
+// shortest-path implementations on the real input.
+func BenchmarkPart1(b *testing.B) {
+	for range b.N {
+		if got := part1("day17-input.txt"); got != 956 {
+			b.Fatalf("part1() = %v, want 956", got)
+		}
+	}
+}
+
+func BenchmarkPart2(b *testing.B) {
+	for range b.N {
+		if got := part2("day17-input.txt"); got != 1106 {
+			b.Fatalf("part2() = %v, want 1106", got)
+		}
+	}
+}
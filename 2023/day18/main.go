@@ -1,13 +1,11 @@
-package main
+package day18
 
 import (
 	"bufio"
-	"flag"
 	"fmt"
 	"log"
 	"os"
 	"regexp"
-	"runtime/pprof"
 	"strconv"
 	"strings"
 
@@ -25,52 +23,13 @@ func (i Instruction) String() string {
 }
 
 func processInstructions(instructions []Instruction) []utils.Position {
-	type GridlessPosition struct {
-		x int
-		y int
-	}
-	minX, maxX, minY, maxY := 0, 0, 0, 0
-	currPos := GridlessPosition{0, 0}
-	for _, instruction := range instructions {
-		switch instruction.direction {
-		case utils.North:
-			currPos.y -= instruction.distance
-		case utils.South:
-			currPos.y += instruction.distance
-		case utils.East:
-			currPos.x += instruction.distance
-		case utils.West:
-			currPos.x -= instruction.distance
-		}
-		if minX > currPos.x {
-			minX = currPos.x
-		}
-		if maxX < currPos.x {
-			maxX = currPos.x
-		}
-		if minY > currPos.y {
-			minY = currPos.y
-		}
-		if maxY < currPos.y {
-			maxY = currPos.y
-		}
-	}
-	currPos2 := utils.Position{X: -minX, Y: -minY}
+	currPos := utils.Position{X: 0, Y: 0}
 	path := make([]utils.Position, 0)
-	path = append(path, currPos2)
+	path = append(path, currPos)
 	for _, instruction := range instructions {
 		for i := 0; i < instruction.distance; i++ {
-			switch instruction.direction {
-			case utils.North:
-				currPos2.Y--
-			case utils.South:
-				currPos2.Y++
-			case utils.East:
-				currPos2.X++
-			case utils.West:
-				currPos2.X--
-			}
-			path = append(path, currPos2)
+			currPos = currPos.Step(instruction.direction, 1)
+			path = append(path, currPos)
 		}
 	}
 	return path
@@ -88,25 +47,14 @@ func parseFile(fname string) []Instruction {
 		t := strings.TrimSpace(scanner.Text())
 		re := regexp.MustCompile(`(\w) (\d+) \(#([0-9a-f]{6})\)`)
 		matches := re.FindStringSubmatch(t)
-		directionStr := matches[1]
-		var direction utils.Direction
-		switch directionStr {
-		case "R":
-			direction = utils.East
-		case "L":
-			direction = utils.West
-		case "U":
-			direction = utils.North
-		case "D":
-			direction = utils.South
-		}
+		direction := utils.MustParseDirection(rune(matches[1][0]), utils.URDL)
 
 		distance, err := strconv.Atoi(matches[2])
 		if err != nil {
 			log.Fatal(err)
 		}
 		color := matches[3]
-		instruction := Instruction{direction: utils.Direction(direction), distance: distance, color: color}
+		instruction := Instruction{direction: direction, distance: distance, color: color}
 		instructions = append(instructions, instruction)
 	}
 	return instructions
@@ -121,62 +69,20 @@ func part1(fname string) int {
 	return utils.GetInteriorPoints(path).NumPoints() + len(path)
 }
 
-func processInstructions_Shoelace(instructions []Instruction) []utils.Position {
-	type GridlessPosition struct {
-		x int
-		y int
-	}
-	minX, maxX, minY, maxY := 0, 0, 0, 0
-	currPos := GridlessPosition{0, 0}
-	for _, instruction := range instructions {
-		switch instruction.direction {
-		case utils.North:
-			currPos.y -= instruction.distance
-		case utils.South:
-			currPos.y += instruction.distance
-		case utils.East:
-			currPos.x += instruction.distance
-		case utils.West:
-			currPos.x -= instruction.distance
-		}
-		if minX > currPos.x {
-			minX = currPos.x
-		}
-		if maxX < currPos.x {
-			maxX = currPos.x
-		}
-		if minY > currPos.y {
-			minY = currPos.y
-		}
-		if maxY < currPos.y {
-			maxY = currPos.y
-		}
-	}
-	currPos2 := utils.Position{X: 0, Y: 0}
-	path := make([]utils.Position, 0)
-	path = append(path, currPos2)
-	fmt.Println(currPos2)
-	for _, instruction := range instructions {
-		switch instruction.direction {
-		case utils.North:
-			currPos2.Y += instruction.distance
-		case utils.South:
-			currPos2.Y -= instruction.distance
-		case utils.East:
-			currPos2.X += instruction.distance
-		case utils.West:
-			currPos2.X -= instruction.distance
-		}
-		fmt.Println(currPos2)
-		path = append(path, currPos2)
+// lagoonSize counts the trench cells and everything they enclose. Each cell is a lattice
+// point of the polygon traced by the instructions, so that's its boundary points plus
+// its interior points, without ever visiting a cell.
+func lagoonSize(instructions []Instruction) int {
+	moves := make([]utils.PolygonMove, len(instructions))
+	for i, instruction := range instructions {
+		moves[i] = utils.PolygonMove{Direction: instruction.direction, Length: instruction.distance}
 	}
-	return path
+	polygon := utils.MustNewPolygonFromMoves(utils.Position{X: 0, Y: 0}, moves)
+	return polygon.LatticeBoundaryPoints() + polygon.LatticeInteriorPoints()
 }
 
 func part1_Shoelace(fname string) int {
-	instructions := parseFile(fname)
-	path := processInstructions(instructions)
-	return utils.ShoelaceArea(path) + (len(path) / 2) + 1
+	return lagoonSize(parseFile(fname))
 }
 
 func part2(fname string) int {
@@ -188,49 +94,17 @@ func part2(fname string) int {
 			log.Fatal(err)
 		}
 		instruction.distance = int(newDistance)
-		switch instruction.color[5] {
-		case '0':
-			instruction.direction = utils.East
-		case '1':
-			instruction.direction = utils.South
-		case '2':
-			instruction.direction = utils.West
-		case '3':
-			instruction.direction = utils.North
-		}
+		instruction.direction = utils.MustParseDirection(rune(instruction.color[5]), utils.DigitsRDLU)
 	}
 
 	//for _, instr := range instructions {
 	//	fmt.Println(instr)
 	//}
-	path := processInstructions(instructions)
-	//fmt.Println(path)
-	if path[len(path)-1] == path[0] {
-		path = path[:len(path)-1]
-	}
-	return utils.ShoelaceArea(path) + (len(path) / 2) + 1
-
-	// return utils.GetInteriorPoints(path).NumPoints() + len(path)
+	return lagoonSize(instructions)
 }
 
-func main() {
-	var cpuprofile = flag.String("cpuprofile", "", "write cpu profile to file")
-	flag.Parse()
-	if *cpuprofile != "" {
-		f, err := os.Create(*cpuprofile)
-		if err != nil {
-			log.Fatal(err)
-		}
-		pprof.StartCPUProfile(f)
-		defer pprof.StopCPUProfile()
-	}
-
-	fmt.Println(part1("day18-input-easy.txt"))
-	//fmt.Println(part1("day18-input.txt"))
-
-	fmt.Println(part1_Shoelace("day18-input-easy.txt"))
-	fmt.Println(part1_Shoelace("day18-input.txt"))
-
-	fmt.Println(part2("day18-input-easy.txt"))
-	fmt.Println(part2("day18-input.txt"))
+func init() {
+	// part1 lists the interior cells row by row, which gets the same answer but needs
+	// every trench cell, so it can't handle part 2's distances.
+	utils.RegisterPuzzle(2023, 18, part1_Shoelace, part2)
 }
@@ -0,0 +1,11 @@
+package day18
+
+import "testing"
+
+func TestInteriorPointsMatchesPolygon(t *testing.T) {
+	for _, fname := range []string{"day18-input-easy.txt", "day18-input.txt"} {
+		if got, want := part1(fname), part1_Shoelace(fname); got != want {
+			t.Errorf("part1(%s) = %v, want %v", fname, got, want)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package day19
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/nsanch/aoc/aoc2023/utils"
+)
+
+// DecisionNode is one step of a compiled DecisionTree. Leaves have an Outcome of "A" or
+// "R"; other nodes send parts matching Rule to Pass and everything else to Fail.
+type DecisionNode struct {
+	Outcome string
+	Rule    Rule
+	Pass    *DecisionNode
+	Fail    *DecisionNode
+	// Bounds holds every part that reaches this node, as disjoint boxes.
+	Bounds []Bounds
+}
+
+func (n *DecisionNode) IsLeaf() bool {
+	return n.Pass == nil
+}
+
+// RuleRef names one rule of a workflow by its position, counting from 0.
+type RuleRef struct {
+	Workflow string
+	Index    int
+}
+
+// DecisionTree is a set of workflows flattened into a single tree of comparisons,
+// along with whatever looked suspicious while building it.
+type DecisionTree struct {
+	Root *DecisionNode
+	// UnreachableRules never send a part anywhere, either because earlier rules catch
+	// everything first or because their condition can't hold for the parts that get there.
+	UnreachableRules []RuleRef
+	// UnreachableWorkflows can't be reached from "in" at all.
+	UnreachableWorkflows []string
+	// AlwaysAccept and AlwaysReject list workflows that send every part to the same
+	// place, so they could be replaced by A or R.
+	AlwaysAccept []string
+	AlwaysReject []string
+	// Cycles lists loops between workflows that no part can actually go around. One
+	// that a part can go around makes CompileWorkflows fail instead.
+	Cycles [][]string
+
+	workflows  map[string]Workflow
+	attributes []string
+}
+
+type compiler struct {
+	workflows map[string]Workflow
+	visited   map[string]bool
+	onStack   map[string]bool
+	fired     map[RuleRef]bool
+}
+
+func newCompiler(workflows map[string]Workflow) *compiler {
+	return &compiler{
+		workflows: workflows,
+		visited:   make(map[string]bool),
+		onStack:   make(map[string]bool),
+		fired:     make(map[RuleRef]bool),
+	}
+}
+
+// compile builds the tree for parts within bounds arriving at dest.
+func (c *compiler) compile(dest string, bounds []Bounds) (*DecisionNode, error) {
+	if dest == "A" || dest == "R" {
+		return &DecisionNode{Outcome: dest, Bounds: bounds}, nil
+	}
+	if c.onStack[dest] {
+		return nil, fmt.Errorf("%w: parts like %v loop back to workflow %s", utils.ErrCycle, bounds[0], dest)
+	}
+	c.visited[dest] = true
+	c.onStack[dest] = true
+	defer delete(c.onStack, dest)
+	return c.compileRules(c.workflows[dest], 0, bounds)
+}
+
+// compileRules builds the tree for parts within bounds that failed every rule of
+// workflow before index i. Branches no part can take are left out, and a comparison
+// whose branches end up the same is replaced by that outcome.
+func (c *compiler) compileRules(workflow Workflow, i int, bounds []Bounds) (*DecisionNode, error) {
+	if i == len(workflow.rules) {
+		return nil, fmt.Errorf("parts like %v fall off the end of workflow %s", bounds[0], workflow.name)
+	}
+	rule := workflow.rules[i]
+	pass, fail := make([]Bounds, 0), make([]Bounds, 0)
+	for _, b := range bounds {
+		p, f := rule.split(b)
+		pass = append(pass, p...)
+		fail = append(fail, f...)
+	}
+	if len(pass) == 0 {
+		return c.compileRules(workflow, i+1, fail)
+	}
+	c.fired[RuleRef{Workflow: workflow.name, Index: i}] = true
+	if len(fail) == 0 {
+		return c.compile(rule.GetDestination(), pass)
+	}
+
+	passNode, err := c.compile(rule.GetDestination(), pass)
+	if err != nil {
+		return nil, err
+	}
+	failNode, err := c.compileRules(workflow, i+1, fail)
+	if err != nil {
+		return nil, err
+	}
+	if passNode.IsLeaf() && failNode.IsLeaf() && passNode.Outcome == failNode.Outcome {
+		return &DecisionNode{Outcome: passNode.Outcome, Bounds: bounds}, nil
+	}
+	return &DecisionNode{Rule: rule, Pass: passNode, Fail: failNode, Bounds: bounds}, nil
+}
+
+// CompileWorkflows flattens the workflows, starting from "in", into a DecisionTree for
+// parts with the given attributes. It fails if a workflow is missing or defined twice,
+// if a rule reads some other attribute, if a part can fall off the end of a workflow,
+// or if a part can go around a loop of workflows forever.
+func CompileWorkflows(workflows []Workflow, attributes []string) (*DecisionTree, error) {
+	workflowMap := make(map[string]Workflow)
+	for _, workflow := range workflows {
+		if _, ok := workflowMap[workflow.name]; ok {
+			return nil, fmt.Errorf("workflow %s is defined twice", workflow.name)
+		}
+		workflowMap[workflow.name] = workflow
+	}
+	for _, workflow := range workflows {
+		for _, rule := range workflow.rules {
+			dest := rule.GetDestination()
+			if _, ok := workflowMap[dest]; !ok && dest != "A" && dest != "R" {
+				return nil, fmt.Errorf("workflow %s sends parts to unknown workflow %s", workflow.name, dest)
+			}
+			if rule.condition == nil {
+				continue
+			}
+			var unknown *Comparison
+			walkComparisons(rule.condition, func(c Comparison) {
+				if unknown == nil && !slices.Contains(attributes, c.Attribute) {
+					unknown = &c
+				}
+			})
+			if unknown != nil {
+				return nil, &SyntaxError{
+					Line:   workflow.line,
+					Column: unknown.Column,
+					Msg:    fmt.Sprintf("workflow %s reads unknown attribute %s", workflow.name, unknown.Attribute),
+				}
+			}
+		}
+	}
+	if _, ok := workflowMap["in"]; !ok {
+		return nil, errors.New(`there's no workflow named "in"`)
+	}
+
+	c := newCompiler(workflowMap)
+	root, err := c.compile("in", []Bounds{NewBounds(attributes)})
+	if err != nil {
+		return nil, err
+	}
+	tree := &DecisionTree{Root: root, workflows: workflowMap, attributes: attributes}
+	for _, workflow := range workflows {
+		if !c.visited[workflow.name] {
+			tree.UnreachableWorkflows = append(tree.UnreachableWorkflows, workflow.name)
+			continue
+		}
+		for i := range workflow.rules {
+			if ref := (RuleRef{Workflow: workflow.name, Index: i}); !c.fired[ref] {
+				tree.UnreachableRules = append(tree.UnreachableRules, ref)
+			}
+		}
+		// look at the workflow on its own, whatever parts actually reach it.
+		alone, err := newCompiler(workflowMap).compile(workflow.name, []Bounds{NewBounds(attributes)})
+		if err == nil && alone.Outcome == "A" {
+			tree.AlwaysAccept = append(tree.AlwaysAccept, workflow.name)
+		} else if err == nil && alone.Outcome == "R" {
+			tree.AlwaysReject = append(tree.AlwaysReject, workflow.name)
+		}
+	}
+
+	graph := makeWorkflowGraph(workflows)
+	for _, component := range graph.StronglyConnectedComponents() {
+		selfLoop := slices.ContainsFunc(graph[component[0]], func(n utils.Neighbor[string]) bool {
+			return n.Value == component[0]
+		})
+		if len(component) > 1 || selfLoop {
+			tree.Cycles = append(tree.Cycles, component)
+		}
+	}
+	return tree, nil
+}
+
+// Evaluate returns "A" or "R" for the part.
+func (t *DecisionTree) Evaluate(part Part) string {
+	node := t.Root
+	for !node.IsLeaf() {
+		if node.Rule.ShouldApply(part) {
+			node = node.Pass
+		} else {
+			node = node.Fail
+		}
+	}
+	return node.Outcome
+}
+
+// AcceptedRegions returns the bounds of every accepted leaf. They never overlap.
+func (t *DecisionTree) AcceptedRegions() []Bounds {
+	ret := make([]Bounds, 0)
+	var walk func(node *DecisionNode)
+	walk = func(node *DecisionNode) {
+		if node.IsLeaf() {
+			if node.Outcome == "A" {
+				ret = append(ret, node.Bounds...)
+			}
+			return
+		}
+		walk(node.Pass)
+		walk(node.Fail)
+	}
+	walk(t.Root)
+	return ret
+}
+
+// NumAccepted counts the distinct parts the workflows accept.
+func (t *DecisionTree) NumAccepted() int {
+	var accepted utils.BoxSet
+	for _, region := range t.AcceptedRegions() {
+		accepted.Add(region.Box)
+	}
+	return int(accepted.Volume())
+}
+
+// WriteAcceptedTable writes one row per accepted region with the inclusive range of
+// each rating and the number of parts in it.
+func (t *DecisionTree) WriteAcceptedTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, attribute := range t.attributes {
+		fmt.Fprintf(tw, "%s\t", attribute)
+	}
+	fmt.Fprintln(tw, "count")
+	for _, region := range t.AcceptedRegions() {
+		for _, attribute := range t.attributes {
+			r, _ := region.Range(attribute)
+			fmt.Fprintf(tw, "%d-%d\t", r.Start, r.End-1)
+		}
+		fmt.Fprintln(tw, region.NumPossibleValues())
+	}
+	return tw.Flush()
+}
+
+// Warnings describes everything suspicious about the workflows, one line each.
+func (t *DecisionTree) Warnings() []string {
+	ret := make([]string, 0)
+	for _, name := range t.UnreachableWorkflows {
+		ret = append(ret, fmt.Sprintf("workflow %s is never reached", name))
+	}
+	for _, ref := range t.UnreachableRules {
+		ret = append(ret, fmt.Sprintf("workflow %s rule %d (%v) never applies", ref.Workflow, ref.Index, t.workflows[ref.Workflow].rules[ref.Index]))
+	}
+	for _, name := range t.AlwaysAccept {
+		ret = append(ret, fmt.Sprintf("workflow %s always accepts", name))
+	}
+	for _, name := range t.AlwaysReject {
+		ret = append(ret, fmt.Sprintf("workflow %s always rejects", name))
+	}
+	for _, cycle := range t.Cycles {
+		ret = append(ret, fmt.Sprintf("workflows %v form a loop", cycle))
+	}
+	return ret
+}
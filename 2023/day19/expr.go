@@ -0,0 +1,421 @@
+package day19
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/nsanch/aoc/aoc2023/utils"
+)
+
+// SyntaxError says where in a workflow or part line parsing went wrong. Columns count
+// bytes from 1; Line is 0 when the error came from parsing a single line.
+type SyntaxError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+	}
+	return fmt.Sprintf("column %d: %s", e.Column, e.Msg)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenSymbol
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// pos is the byte offset of the token in the line.
+	pos int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of line"
+	}
+	return strconv.Quote(t.text)
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// the two-character symbols have to be tried before the one-character ones.
+var symbols = []string{"<=", ">=", "==", "!=", "&&", "||", "<", ">", "=", "(", ")", "{", "}", ",", ":"}
+
+func lex(line string) ([]token, error) {
+	tokens := make([]token, 0)
+	for i := 0; i < len(line); {
+		c := line[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case isLetter(c):
+			start := i
+			for i < len(line) && (isLetter(line[i]) || isDigit(line[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: line[start:i], pos: start})
+		case isDigit(c):
+			start := i
+			for i < len(line) && isDigit(line[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: line[start:i], pos: start})
+		default:
+			matched := false
+			for _, symbol := range symbols {
+				if strings.HasPrefix(line[i:], symbol) {
+					tokens = append(tokens, token{kind: tokenSymbol, text: symbol, pos: i})
+					i += len(symbol)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, &SyntaxError{Column: i + 1, Msg: fmt.Sprintf("unexpected character %q", c)}
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(line)}), nil
+}
+
+type parser struct {
+	tokens []token
+	i      int
+}
+
+func newParser(line string) (*parser, error) {
+	tokens, err := lex(line)
+	if err != nil {
+		return nil, err
+	}
+	return &parser{tokens: tokens}, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+// peekAt looks ahead without consuming anything; the last token is always EOF.
+func (p *parser) peekAt(offset int) token {
+	return p.tokens[min(p.i+offset, len(p.tokens)-1)]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return &SyntaxError{Column: t.pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) isSymbol(text string) bool {
+	t := p.peek()
+	return t.kind == tokenSymbol && t.text == text
+}
+
+func (p *parser) expectSymbol(text string) error {
+	if t := p.next(); t.kind != tokenSymbol || t.text != text {
+		return p.errorf(t, "expected %q, found %v", text, t)
+	}
+	return nil
+}
+
+func (p *parser) expectIdent(what string) (token, error) {
+	t := p.next()
+	if t.kind != tokenIdent {
+		return t, p.errorf(t, "expected %s, found %v", what, t)
+	}
+	return t, nil
+}
+
+func (p *parser) expectNumber() (int, error) {
+	t := p.next()
+	if t.kind != tokenNumber {
+		return 0, p.errorf(t, "expected a number, found %v", t)
+	}
+	n, err := strconv.Atoi(t.text)
+	if err != nil {
+		return 0, p.errorf(t, "number %s is too large", t.text)
+	}
+	return n, nil
+}
+
+func (p *parser) expectEOF() error {
+	if t := p.peek(); t.kind != tokenEOF {
+		return p.errorf(t, "unexpected %v after the end", t)
+	}
+	return nil
+}
+
+// Expr is a parsed rule condition.
+type Expr interface {
+	// Eval checks the condition against one part.
+	Eval(part Part) bool
+	// Split divides b into disjoint boxes where the condition holds and boxes where it
+	// doesn't. Empty boxes are left out.
+	Split(b Bounds) ([]Bounds, []Bounds)
+	String() string
+}
+
+// Comparison checks one attribute against a constant. Conditions written the other way
+// round, like 10<x, are flipped when parsed.
+type Comparison struct {
+	Attribute string
+	Op        string
+	Value     int
+	// Column is where the attribute appears in its line, for error messages.
+	Column int
+}
+
+var flippedOps = map[string]string{"<": ">", ">": "<", "<=": ">=", ">=": "<=", "==": "==", "!=": "!="}
+
+func (c Comparison) Eval(part Part) bool {
+	value := part.Get(c.Attribute)
+	switch c.Op {
+	case "<":
+		return value < c.Value
+	case "<=":
+		return value <= c.Value
+	case ">":
+		return value > c.Value
+	case ">=":
+		return value >= c.Value
+	case "==":
+		return value == c.Value
+	default:
+		return value != c.Value
+	}
+}
+
+// matching returns every value of the attribute that passes.
+func (c Comparison) matching() utils.IntervalSet {
+	v := int64(c.Value)
+	all := utils.Interval{Start: math.MinInt64, End: math.MaxInt64}
+	// all stops short of MaxInt64, so v+1 can stop there too instead of overflowing.
+	next := v
+	if v < all.End {
+		next = v + 1
+	}
+	switch c.Op {
+	case "<":
+		return utils.NewIntervalSet(utils.Interval{Start: all.Start, End: v})
+	case "<=":
+		return utils.NewIntervalSet(utils.Interval{Start: all.Start, End: next})
+	case ">":
+		return utils.NewIntervalSet(utils.Interval{Start: next, End: all.End})
+	case ">=":
+		return utils.NewIntervalSet(utils.Interval{Start: v, End: all.End})
+	case "==":
+		return utils.NewIntervalSet(utils.Interval{Start: v, End: next})
+	default:
+		return utils.NewIntervalSet(utils.Interval{Start: v, End: next}).Complement(all)
+	}
+}
+
+func (c Comparison) Split(b Bounds) ([]Bounds, []Bounds) {
+	r, ok := b.Range(c.Attribute)
+	if !ok {
+		log.Panicf("can't split %v on unknown attribute %s", b, c.Attribute)
+	}
+	values := utils.NewIntervalSet(r)
+	toBounds := func(s utils.IntervalSet) []Bounds {
+		ret := make([]Bounds, 0)
+		for iv := range s.All() {
+			ret = append(ret, Bounds{b.With(c.Attribute, iv)})
+		}
+		return ret
+	}
+	matching := c.matching()
+	return toBounds(values.Intersection(matching)), toBounds(values.Difference(matching))
+}
+
+func (c Comparison) String() string {
+	return fmt.Sprintf("%s%s%d", c.Attribute, c.Op, c.Value)
+}
+
+type AndExpr struct {
+	Left, Right Expr
+}
+
+func (e AndExpr) Eval(part Part) bool {
+	return e.Left.Eval(part) && e.Right.Eval(part)
+}
+
+func (e AndExpr) Split(b Bounds) ([]Bounds, []Bounds) {
+	leftPass, fail := e.Left.Split(b)
+	pass := make([]Bounds, 0)
+	for _, lp := range leftPass {
+		p, f := e.Right.Split(lp)
+		pass = append(pass, p...)
+		fail = append(fail, f...)
+	}
+	return pass, fail
+}
+
+func (e AndExpr) String() string {
+	parenthesize := func(side Expr) string {
+		if _, ok := side.(OrExpr); ok {
+			return "(" + side.String() + ")"
+		}
+		return side.String()
+	}
+	return parenthesize(e.Left) + " && " + parenthesize(e.Right)
+}
+
+type OrExpr struct {
+	Left, Right Expr
+}
+
+func (e OrExpr) Eval(part Part) bool {
+	return e.Left.Eval(part) || e.Right.Eval(part)
+}
+
+func (e OrExpr) Split(b Bounds) ([]Bounds, []Bounds) {
+	pass, leftFail := e.Left.Split(b)
+	fail := make([]Bounds, 0)
+	for _, lf := range leftFail {
+		p, f := e.Right.Split(lf)
+		pass = append(pass, p...)
+		fail = append(fail, f...)
+	}
+	return pass, fail
+}
+
+func (e OrExpr) String() string {
+	return e.Left.String() + " || " + e.Right.String()
+}
+
+// walkComparisons calls visit on every Comparison in e.
+func walkComparisons(e Expr, visit func(Comparison)) {
+	switch e := e.(type) {
+	case Comparison:
+		visit(e)
+	case AndExpr:
+		walkComparisons(e.Left, visit)
+		walkComparisons(e.Right, visit)
+	case OrExpr:
+		walkComparisons(e.Left, visit)
+		walkComparisons(e.Right, visit)
+	}
+}
+
+// parseOr parses a condition: comparisons joined by && and ||, where && binds tighter,
+// with parentheses for grouping.
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isSymbol("||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = OrExpr{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isSymbol("&&") {
+		p.next()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		left = AndExpr{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	if !p.isSymbol("(") {
+		return p.parseComparison()
+	}
+	p.next()
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	left := p.next()
+	if left.kind != tokenIdent && left.kind != tokenNumber {
+		return nil, p.errorf(left, "expected an attribute or a number, found %v", left)
+	}
+	op := p.next()
+	if _, ok := flippedOps[op.text]; op.kind != tokenSymbol || !ok {
+		return nil, p.errorf(op, "expected a comparison, found %v", op)
+	}
+	right := p.next()
+	if right.kind != tokenIdent && right.kind != tokenNumber {
+		return nil, p.errorf(right, "expected an attribute or a number, found %v", right)
+	}
+	if left.kind == right.kind {
+		return nil, p.errorf(left, "a comparison needs one attribute and one number")
+	}
+	opText := op.text
+	if left.kind == tokenNumber {
+		left, right = right, left
+		opText = flippedOps[opText]
+	}
+	value, err := strconv.Atoi(right.text)
+	if err != nil {
+		return nil, p.errorf(right, "number %s is too large", right.text)
+	}
+	return Comparison{Attribute: left.text, Op: opText, Value: value, Column: left.pos + 1}, nil
+}
+
+// parseRule parses either condition:destination or just a destination.
+func (p *parser) parseRule() (Rule, error) {
+	if p.peek().kind == tokenIdent {
+		after := p.peekAt(1)
+		if after.kind == tokenEOF || (after.kind == tokenSymbol && (after.text == "," || after.text == "}")) {
+			return Rule{destination: p.next().text}, nil
+		}
+	}
+	condition, err := p.parseOr()
+	if err != nil {
+		return Rule{}, err
+	}
+	if err := p.expectSymbol(":"); err != nil {
+		return Rule{}, err
+	}
+	dest, err := p.expectIdent("a destination workflow")
+	if err != nil {
+		return Rule{}, err
+	}
+	return Rule{condition: condition, destination: dest.text}, nil
+}
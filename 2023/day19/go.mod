@@ -1,4 +1,4 @@
-module github.com/nsanch/aoc/aoc2023/day18
+module github.com/nsanch/aoc/aoc2023/day19
 
 go 1.24.1
 
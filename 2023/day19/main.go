@@ -1,384 +1,366 @@
-package main
+package day19
 
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"log"
 	"os"
-	"regexp"
-	"slices"
-	"strconv"
 	"strings"
+
+	"github.com/nsanch/aoc/aoc2023/utils"
 )
 
+// Bounds is the box of parts that could still reach some point in the workflows.
 type Bounds struct {
-	minX, maxX int
-	minM, maxM int
-	minA, maxA int
-	minS, maxS int
+	utils.Box
 }
 
-func NewBounds() Bounds {
-	return Bounds{
-		minX: 1,
-		maxX: 4000,
-		minM: 1,
-		maxM: 4000,
-		minA: 1,
-		maxA: 4000,
-		minS: 1,
-		maxS: 4000,
-	}
-}
+// ratingRange holds every rating a part can have.
+var ratingRange = utils.Interval{Start: 1, End: 4001}
 
-func (b Bounds) NumPossibleValues() int {
-	return (b.maxX - b.minX + 1) *
-		(b.maxM - b.minM + 1) *
-		(b.maxA - b.minA + 1) *
-		(b.maxS - b.minS + 1)
+// NewBounds allows every rating from 1 to 4000 for each attribute.
+func NewBounds(attributes []string) Bounds {
+	return Bounds{utils.UniformBox(attributes, ratingRange)}
 }
 
-func (b *Bounds) Clone() Bounds {
-	return Bounds{
-		minX: b.minX,
-		maxX: b.maxX,
-		minM: b.minM,
-		maxM: b.maxM,
-		minA: b.minA,
-		maxA: b.maxA,
-		minS: b.minS,
-		maxS: b.maxS,
-	}
+func (b Bounds) NumPossibleValues() int {
+	return int(b.Volume())
 }
 
 type Rule struct {
-	autoAccept         bool
-	conditionReads     string
-	conditionIsGT      bool
-	conditionThreshold int
-	destination        string
+	// condition is nil for a rule that always applies.
+	condition   Expr
+	destination string
 }
 
 func (r Rule) String() string {
-	if r.autoAccept {
+	if r.condition == nil {
 		return fmt.Sprintf("Rule: autoAccept %s", r.destination)
-	} else if r.conditionIsGT {
-		return fmt.Sprintf("Rule: %s > %d -> %s", r.conditionReads, r.conditionThreshold, r.destination)
-	} else {
-		return fmt.Sprintf("Rule: %s < %d -> %s", r.conditionReads, r.conditionThreshold, r.destination)
 	}
+	return fmt.Sprintf("Rule: %v -> %s", r.condition, r.destination)
 }
 
-func NewRule(conditionStr string) Rule {
-	if !strings.Contains(conditionStr, ":") {
-		return Rule{autoAccept: true, destination: conditionStr}
-	}
-	re := regexp.MustCompile(`(x|m|a|s)(>|<)(\d+):(\w+)`)
-	matches := re.FindStringSubmatch(conditionStr)
-	if len(matches) != 5 {
-		log.Panicf("Invalid condition string %s", conditionStr)
+// NewRule parses a single rule like a<2006&&m>=10:qkq or just a destination.
+func NewRule(ruleStr string) (Rule, error) {
+	p, err := newParser(ruleStr)
+	if err != nil {
+		return Rule{}, err
 	}
-	threshold, err := strconv.Atoi(matches[3])
+	rule, err := p.parseRule()
 	if err != nil {
-		log.Panicf("Invalid threshold value %s", matches[3])
+		return Rule{}, err
 	}
-
-	return Rule{
-		conditionReads:     matches[1],
-		conditionIsGT:      matches[2] == ">",
-		conditionThreshold: threshold,
-		destination:        matches[4],
+	if err := p.expectEOF(); err != nil {
+		return Rule{}, err
 	}
+	return rule, nil
 }
 
 func (r Rule) ShouldApply(part Part) bool {
-	if r.autoAccept {
-		return true
-	}
-	value := 0
-	switch r.conditionReads {
-	case "x":
-		value = part.x
-	case "m":
-		value = part.m
-	case "a":
-		value = part.a
-	case "s":
-		value = part.s
-	default:
-		log.Panicf("Invalid condition reads %s", r.conditionReads)
-	}
-	if r.conditionIsGT {
-		return value > r.conditionThreshold
-	}
-	return value < r.conditionThreshold
+	return r.condition == nil || r.condition.Eval(part)
 }
 
 func (r Rule) GetDestination() string {
 	return r.destination
 }
 
-func (r Rule) ConstrainAcceptableRange(b *Bounds) bool {
-	if r.autoAccept {
-		return true
-	}
-	var minV *int
-	var maxV *int
-	switch r.conditionReads {
-	case "x":
-		minV = &b.minX
-		maxV = &b.maxX
-	case "m":
-		minV = &b.minM
-		maxV = &b.maxM
-	case "a":
-		minV = &b.minA
-		maxV = &b.maxA
-	case "s":
-		minV = &b.minS
-		maxV = &b.maxS
-	default:
-		log.Panicf("Invalid condition reads %s", r.conditionReads)
+// split divides b into the parts that pass the rule and the parts that fail it.
+func (r Rule) split(b Bounds) ([]Bounds, []Bounds) {
+	if r.condition == nil {
+		return []Bounds{b}, nil
 	}
+	return r.condition.Split(b)
+}
 
-	if r.conditionIsGT {
-		if *maxV != 0 && *maxV < r.conditionThreshold {
-			// we know that V must be less than the threshold. So this rule can never pass.
-			return false
-		}
-		*minV = max(*minV, r.conditionThreshold+1)
-		return true
-	} else {
-		if *minV != 0 && *minV >= r.conditionThreshold {
-			// we know that V must be >= than the threshold. So this rule can never pass.
-			return false
-		}
-		*maxV = min(*maxV, r.conditionThreshold-1)
-		return true
-	}
+type Workflow struct {
+	name  string
+	rules []Rule
+	// line is where ParseFile found the workflow, or 0 if it came from NewWorkflow.
+	line int
 }
 
-func (r Rule) ConstrainToFailureRange(b *Bounds) bool {
-	if r.autoAccept {
-		return false
+// NewWorkflow parses a line like px{a<2006:qkq,m>2090:A,rfg}.
+func NewWorkflow(workflowStr string) (Workflow, error) {
+	p, err := newParser(workflowStr)
+	if err != nil {
+		return Workflow{}, err
 	}
-
-	var minV *int
-	var maxV *int
-	switch r.conditionReads {
-	case "x":
-		minV = &b.minX
-		maxV = &b.maxX
-	case "m":
-		minV = &b.minM
-		maxV = &b.maxM
-	case "a":
-		minV = &b.minA
-		maxV = &b.maxA
-	case "s":
-		minV = &b.minS
-		maxV = &b.maxS
-	default:
-		log.Panicf("Invalid condition reads %s", r.conditionReads)
+	name, err := p.expectIdent("a workflow name")
+	if err != nil {
+		return Workflow{}, err
 	}
-
-	if r.conditionIsGT {
-		// we know we fail this rule, so V must be <= threshold.
-		if *minV != 0 && *minV > r.conditionThreshold {
-			// we know that V must be greater than the threshold. So this rule can never pass.
-			return false
+	if err := p.expectSymbol("{"); err != nil {
+		return Workflow{}, err
+	}
+	rules := make([]Rule, 0)
+	for {
+		rule, err := p.parseRule()
+		if err != nil {
+			return Workflow{}, err
 		}
-		*maxV = min(*maxV, r.conditionThreshold)
-		return true
-	} else {
-		// similar to above. we can only pass if we're >= threshold. so if max is < than threshold,
-		// this can never pass.
-		if *maxV != 0 && *maxV < r.conditionThreshold {
-			// we know that V must be less than the threshold. So this rule can never pass.
-			return false
+		rules = append(rules, rule)
+		if !p.isSymbol(",") {
+			break
 		}
-		*minV = max(*minV, r.conditionThreshold)
-		return true
+		p.next()
 	}
-}
-
-type Workflow struct {
-	name  string
-	rules []Rule
-}
-
-func NewWorkflow(workflowStr string) Workflow {
-	re := regexp.MustCompile(`^([a-z0-9]+){([^}]+)}$`)
-	matches := re.FindStringSubmatch(workflowStr)
-	if len(matches) != 3 {
-		log.Panicf("Invalid workflow string %s", workflowStr)
-	}
-	name := matches[1]
-	rulesStr := matches[2]
-	rulesStrings := strings.Split(rulesStr, ",")
-	rules := make([]Rule, len(rulesStrings))
-	for i, ruleStr := range rulesStrings {
-		rules[i] = NewRule(ruleStr)
+	if err := p.expectSymbol("}"); err != nil {
+		return Workflow{}, err
+	}
+	if err := p.expectEOF(); err != nil {
+		return Workflow{}, err
 	}
 	return Workflow{
-		name:  name,
+		name:  name.text,
 		rules: rules,
-	}
+	}, nil
 }
 
+// Part has a rating for each of its attributes.
 type Part struct {
-	x, m, a, s int
+	// attributes keeps the order the line listed them in.
+	attributes []string
+	ratings    map[string]int
 }
 
-func NewPart(line string) Part {
-	re := regexp.MustCompile(`{x=(\d+),m=(\d+),a=(\d+),s=(\d+)}`)
-	matches := re.FindStringSubmatch(line)
-	if len(matches) != 5 {
-		log.Panicf("Invalid part string %s", line)
-	}
-	x, err := strconv.Atoi(matches[1])
+// NewPart parses a line like {x=787,m=2655,a=1222,s=2876}. Ratings go from 1 to 4000.
+func NewPart(line string) (Part, error) {
+	p, err := newParser(line)
 	if err != nil {
-		log.Panicf("Invalid x value %s", matches[1])
+		return Part{}, err
 	}
-	m, err := strconv.Atoi(matches[2])
-	if err != nil {
-		log.Panicf("Invalid m value %s", matches[2])
+	if err := p.expectSymbol("{"); err != nil {
+		return Part{}, err
 	}
-	a, err := strconv.Atoi(matches[3])
-	if err != nil {
-		log.Panicf("Invalid a value %s", matches[3])
+	part := Part{ratings: make(map[string]int)}
+	for {
+		attribute, err := p.expectIdent("an attribute")
+		if err != nil {
+			return Part{}, err
+		}
+		if _, ok := part.ratings[attribute.text]; ok {
+			return Part{}, p.errorf(attribute, "attribute %s is listed twice", attribute.text)
+		}
+		if err := p.expectSymbol("="); err != nil {
+			return Part{}, err
+		}
+		ratingToken := p.peek()
+		rating, err := p.expectNumber()
+		if err != nil {
+			return Part{}, err
+		}
+		// the compiled workflows only know about ratings in this range.
+		if !ratingRange.Contains(int64(rating)) {
+			return Part{}, p.errorf(ratingToken, "rating %d is outside %d-%d", rating, ratingRange.Start, ratingRange.End-1)
+		}
+		part.attributes = append(part.attributes, attribute.text)
+		part.ratings[attribute.text] = rating
+		if !p.isSymbol(",") {
+			break
+		}
+		p.next()
 	}
-	s, err := strconv.Atoi(matches[4])
-	if err != nil {
-		log.Panicf("Invalid s value %s", matches[4])
+	if err := p.expectSymbol("}"); err != nil {
+		return Part{}, err
 	}
-	return Part{
-		x: x,
-		m: m,
-		a: a,
-		s: s,
+	if err := p.expectEOF(); err != nil {
+		return Part{}, err
 	}
+	return part, nil
+}
+
+// Get returns the part's rating for an attribute, or 0 if it doesn't have one. ParseFile
+// makes sure every part has the attributes the rules read.
+func (p Part) Get(attribute string) int {
+	return p.ratings[attribute]
+}
+
+func (p Part) String() string {
+	ratings := make([]string, len(p.attributes))
+	for i, attribute := range p.attributes {
+		ratings[i] = fmt.Sprintf("%s=%d", attribute, p.ratings[attribute])
+	}
+	return "{" + strings.Join(ratings, ",") + "}"
 }
 
 func (p Part) PartScore() int {
-	return p.x + p.m + p.a + p.s
+	ret := 0
+	for _, rating := range p.ratings {
+		ret += rating
+	}
+	return ret
 }
 
-func ParseFile(fname string) ([]Part, []Workflow) {
+// ParseFile reads the workflows, a blank line, then the parts. Syntax errors say which
+// line and column they're on.
+func ParseFile(fname string) ([]Part, []Workflow, error) {
 	file, err := os.Open(fname)
 	if err != nil {
-		log.Panicf("Failed to open file %s: %v", fname, err)
+		return nil, nil, err
 	}
 	defer file.Close()
 	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	withLine := func(err error) error {
+		if syntaxErr, ok := err.(*SyntaxError); ok {
+			syntaxErr.Line = lineNum
+		}
+		return fmt.Errorf("%s: %w", fname, err)
+	}
 	workflows := make([]Workflow, 0)
 	for scanner.Scan() {
+		lineNum++
 		t := scanner.Text()
-		t = strings.TrimSpace(t)
-		if t == "" {
+		if strings.TrimSpace(t) == "" {
 			break
 		}
-		w := NewWorkflow(t)
+		w, err := NewWorkflow(t)
+		if err != nil {
+			return nil, nil, withLine(err)
+		}
 		//fmt.Println(w)
+		w.line = lineNum
 		workflows = append(workflows, w)
 	}
 
 	parts := make([]Part, 0)
+	read := ruleAttributes(workflows)
 	for scanner.Scan() {
+		lineNum++
 		t := scanner.Text()
-		t = strings.TrimSpace(t)
-		parts = append(parts, NewPart(t))
+		if strings.TrimSpace(t) == "" {
+			continue
+		}
+		part, err := NewPart(t)
+		if err != nil {
+			return nil, nil, withLine(err)
+		}
+		// Get would quietly use 0, which Split never allows for.
+		for _, attribute := range read {
+			if _, ok := part.ratings[attribute]; !ok {
+				return nil, nil, fmt.Errorf("%s: line %d: part has no %s rating, which the rules read", fname, lineNum, attribute)
+			}
+		}
+		parts = append(parts, part)
 	}
 
+	return parts, workflows, scanner.Err()
+}
+
+func mustParseFile(fname string) ([]Part, []Workflow) {
+	parts, workflows, err := ParseFile(fname)
+	if err != nil {
+		log.Panicf("Failed to parse %v", err)
+	}
 	return parts, workflows
 }
 
-func applyWorkflows(part Part, workflowMap map[string]Workflow, currWorkflow string) string {
-	workflow := workflowMap[currWorkflow]
-	for _, rule := range workflow.rules {
-		if rule.ShouldApply(part) {
-			dest := rule.GetDestination()
-			//fmt.Printf("Rule %v applied to part %v, going to %s\n", rule, part, dest)
-			if dest == "A" || dest == "R" {
-				return dest
-			}
-			return applyWorkflows(part, workflowMap, dest)
+// Attributes lists every attribute the parts have, in the order they first appear.
+// With no parts at all, it falls back to whatever the rules read.
+func Attributes(parts []Part, workflows []Workflow) []string {
+	ret := make([]string, 0)
+	seen := make(map[string]bool)
+	add := func(attribute string) {
+		if !seen[attribute] {
+			seen[attribute] = true
+			ret = append(ret, attribute)
+		}
+	}
+	for _, part := range parts {
+		for _, attribute := range part.attributes {
+			add(attribute)
 		}
 	}
-	log.Panic("Should not reach here", part)
-	return ""
+	if len(parts) == 0 {
+		for _, attribute := range ruleAttributes(workflows) {
+			add(attribute)
+		}
+	}
+	return ret
 }
 
-func part1(fname string) int {
-	parts, workflows := ParseFile(fname)
-	workflowMap := make(map[string]Workflow)
+// ruleAttributes lists every attribute the rules read, in the order they first appear.
+func ruleAttributes(workflows []Workflow) []string {
+	ret := make([]string, 0)
+	seen := make(map[string]bool)
 	for _, workflow := range workflows {
-		workflowMap[workflow.name] = workflow
+		for _, rule := range workflow.rules {
+			if rule.condition == nil {
+				continue
+			}
+			walkComparisons(rule.condition, func(c Comparison) {
+				if !seen[c.Attribute] {
+					seen[c.Attribute] = true
+					ret = append(ret, c.Attribute)
+				}
+			})
+		}
 	}
-	out := 0
-	for _, part := range parts {
-		result := applyWorkflows(part, workflowMap, "in")
-		if result == "A" {
-			out += part.PartScore()
+	return ret
+}
+
+// makeWorkflowGraph has an edge from each workflow to everywhere its rules can send a part.
+func makeWorkflowGraph(workflows []Workflow) utils.Graph[string] {
+	graph := make(utils.Graph[string])
+	for _, workflow := range workflows {
+		for _, rule := range workflow.rules {
+			graph.AddEdge(workflow.name, rule.GetDestination(), 1)
 		}
 	}
-	return out
+	return graph
 }
 
-func WalkPaths(workflowMap map[string]Workflow, currWorkflow string, bounds Bounds) []Bounds {
-	if currWorkflow == "A" {
-		// we've reached the end-state, return the bounds.
-		return []Bounds{bounds}
+func mustCompileWorkflows(parts []Part, workflows []Workflow) *DecisionTree {
+	tree, err := CompileWorkflows(workflows, Attributes(parts, workflows))
+	if err != nil {
+		log.Panicf("Workflows can't be applied: %v", err)
 	}
+	return tree
+}
 
-	ret := make([]Bounds, 0)
-	workflow := workflowMap[currWorkflow]
-	for _, rule := range workflow.rules {
-		// ignore the terminal state reject rules.
-		if rule.GetDestination() != "R" {
-			fmt.Printf("evaluating rule for success %s\n", rule.String())
-			b := bounds.Clone()
-			if !rule.ConstrainAcceptableRange(&b) {
-				fmt.Printf("rule %s cannot pass, bounds: %v\n", rule.String(), bounds)
-			} else {
-				fmt.Printf("Recursing! bounds after rule %s: %v\n", rule.String(), b)
-				ret = slices.Concat(ret, WalkPaths(workflowMap, rule.GetDestination(), b))
-				// cannot break here because there could be multiple ways in this ruleset to
-				// get to the same workflow, but we must've failed this rule to keep going, so continue
-				// with the logic below.
-			}
+// Lint parses and compiles the workflows in fname, then writes the warnings about them,
+// one per line, followed by a table of the regions they accept.
+func Lint(fname string, w io.Writer) error {
+	parts, workflows, err := ParseFile(fname)
+	if err != nil {
+		return err
+	}
+	tree, err := CompileWorkflows(workflows, Attributes(parts, workflows))
+	if err != nil {
+		return fmt.Errorf("%s: %w", fname, err)
+	}
+	warnings := tree.Warnings()
+	for _, warning := range warnings {
+		if _, err := fmt.Fprintln(w, warning); err != nil {
+			return err
 		}
-
-		fmt.Printf("evaluating rule for failure %s\n", rule.String())
-		// we must fail this rule to get to the next one.
-		if !rule.ConstrainToFailureRange(&bounds) {
-			fmt.Printf("rule %s cannot fail, bounds: %v\n", rule.String(), bounds)
-			break
+	}
+	if len(warnings) > 0 {
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
 		}
-		fmt.Printf("bounds after rule %s: %v\n", rule.String(), bounds)
 	}
-	return ret
+	return tree.WriteAcceptedTable(w)
 }
 
-func part2(fname string) int {
-	_, workflows := ParseFile(fname)
-	workflowMap := make(map[string]Workflow)
-	for _, workflow := range workflows {
-		workflowMap[workflow.name] = workflow
-	}
-	allPossibleBounds := WalkPaths(workflowMap, "in", NewBounds())
-	ret := 0
-	for _, bounds := range allPossibleBounds {
-		fmt.Println(bounds)
-		ret += bounds.NumPossibleValues()
+func part1(fname string) int {
+	parts, workflows := mustParseFile(fname)
+	tree := mustCompileWorkflows(parts, workflows)
+	out := 0
+	for _, part := range parts {
+		if tree.Evaluate(part) == "A" {
+			out += part.PartScore()
+		}
 	}
-	return ret
+	return out
 }
 
-func main() {
-	fmt.Println(part1("day19-input-easy.txt"))
-	fmt.Println(part1("day19-input.txt"))
+func part2(fname string) int {
+	parts, workflows := mustParseFile(fname)
+	return mustCompileWorkflows(parts, workflows).NumAccepted()
+}
 
-	fmt.Println(part2("day19-input-easy.txt"))
-	fmt.Println(part2("day19-input.txt"))
+func init() {
+	utils.RegisterPuzzle(2023, 19, part1, part2)
 }
@@ -0,0 +1,303 @@
+package day19
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nsanch/aoc/aoc2023/utils"
+)
+
+var partAttributes = []string{"x", "m", "a", "s"}
+
+func mustParseWorkflows(t *testing.T, lines ...string) []Workflow {
+	t.Helper()
+	workflows := make([]Workflow, len(lines))
+	for i, line := range lines {
+		workflow, err := NewWorkflow(line)
+		if err != nil {
+			t.Fatalf("NewWorkflow(%q) = %v", line, err)
+		}
+		workflows[i] = workflow
+	}
+	return workflows
+}
+
+func TestCompileWorkflowsMatchesEasyInput(t *testing.T) {
+	parts, workflows, err := ParseFile("day19-input-easy.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := CompileWorkflows(workflows, Attributes(parts, workflows))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"A", "R", "A", "R", "A"}
+	for i, part := range parts {
+		if got := tree.Evaluate(part); got != want[i] {
+			t.Errorf("Evaluate(%v) = %v, want %v", part, got, want[i])
+		}
+	}
+	if got := tree.NumAccepted(); got != 167409079868000 {
+		t.Errorf("NumAccepted() = %v, want 167409079868000", got)
+	}
+	// lnx{m>1548:A,A} accepts either way.
+	if !slices.Contains(tree.AlwaysAccept, "lnx") || slices.Contains(tree.AlwaysAccept, "crn") {
+		t.Errorf("AlwaysAccept = %v", tree.AlwaysAccept)
+	}
+}
+
+func TestCompileWorkflowsDiagnostics(t *testing.T) {
+	workflows := mustParseWorkflows(t,
+		"in{x<100:aa,x<50:R,bb}",
+		"aa{m>10:A,m>20:R,A}",
+		"bb{x<100:cc,a<5:ee,R}",
+		"cc{s<1:A,bb}",
+		"dd{A}",
+		"ee{m<5:R,R}",
+	)
+	tree, err := CompileWorkflows(workflows, partAttributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantUnreachable := []RuleRef{{"in", 1}, {"aa", 1}, {"bb", 0}}
+	if !slices.Equal(tree.UnreachableRules, wantUnreachable) {
+		t.Errorf("UnreachableRules = %v, want %v", tree.UnreachableRules, wantUnreachable)
+	}
+	if !slices.Equal(tree.UnreachableWorkflows, []string{"cc", "dd"}) {
+		t.Errorf("UnreachableWorkflows = %v", tree.UnreachableWorkflows)
+	}
+	if !slices.Equal(tree.AlwaysAccept, []string{"aa"}) || !slices.Equal(tree.AlwaysReject, []string{"ee"}) {
+		t.Errorf("AlwaysAccept = %v, AlwaysReject = %v", tree.AlwaysAccept, tree.AlwaysReject)
+	}
+	if len(tree.Cycles) != 1 || len(tree.Cycles[0]) != 2 {
+		t.Errorf("Cycles = %v, want bb and cc", tree.Cycles)
+	}
+	if got := tree.NumAccepted(); got != 99*4000*4000*4000 {
+		t.Errorf("NumAccepted() = %v", got)
+	}
+	if len(tree.Warnings()) != 8 {
+		t.Errorf("Warnings() = %v", strings.Join(tree.Warnings(), "\n"))
+	}
+	var table strings.Builder
+	if err := tree.WriteAcceptedTable(&table); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := table.String(), "x     m       a       s       count\n1-99  1-4000  1-4000  1-4000  6336000000000\n"; got != want {
+		t.Errorf("WriteAcceptedTable() = %q, want %q", got, want)
+	}
+}
+
+func TestCompileWorkflowsErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		workflows []string
+	}{
+		{"missing in", []string{"aa{A}"}},
+		{"unknown destination", []string{"in{x<5:zz,A}"}},
+		{"duplicate", []string{"in{A}", "in{R}"}},
+		{"falls off the end", []string{"in{x<5:A,m<5:R}"}},
+		{"live cycle", []string{"in{x<100:aa,A}", "aa{x<50:in,R}"}},
+	}
+	for _, tt := range tests {
+		if _, err := CompileWorkflows(mustParseWorkflows(t, tt.workflows...), partAttributes); err == nil {
+			t.Errorf("%s: CompileWorkflows() succeeded", tt.name)
+		}
+	}
+	_, err := CompileWorkflows(mustParseWorkflows(t, "in{x<100:aa,A}", "aa{x<50:in,R}"), partAttributes)
+	if !errors.Is(err, utils.ErrCycle) {
+		t.Errorf("CompileWorkflows() = %v, want ErrCycle", err)
+	}
+}
+
+func TestLint(t *testing.T) {
+	var out strings.Builder
+	if err := Lint("day19-input-easy.txt", &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "workflow lnx always accepts\n") {
+		t.Errorf("Lint() = %q, want lnx to always accept", out.String())
+	}
+	if !strings.Contains(out.String(), "\n\nx  ") {
+		t.Errorf("Lint() = %q, want the accepted table after the warnings", out.String())
+	}
+	if err := Lint("no-such-file.txt", &out); err == nil {
+		t.Errorf("Lint() of a missing file succeeded")
+	}
+}
+
+func TestNewRule(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"a<2006:qkq", "Rule: a<2006 -> qkq"},
+		{"rfg", "Rule: autoAccept rfg"},
+		{"x >= 10 && (m == 5 || 7 > s):A", "Rule: x>=10 && (m==5 || s<7) -> A"},
+		{"x!=1||x<=3&&m>2:R", "Rule: x!=1 || x<=3 && m>2 -> R"},
+		{"weight > 12:A", "Rule: weight>12 -> A"},
+	}
+	for _, tt := range tests {
+		rule, err := NewRule(tt.rule)
+		if err != nil {
+			t.Errorf("NewRule(%q) = %v", tt.rule, err)
+		} else if got := rule.String(); got != tt.want {
+			t.Errorf("NewRule(%q) = %v, want %v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"px{a<2006:qkq,m>2090:A,rfg", `column 27: expected "}", found end of line`},
+		{"px{a<<2006:A,R}", `column 6: expected an attribute or a number, found "<"`},
+		{"px{a<2006 A,R}", `column 11: expected ":", found "A"`},
+		{"px{a<2006:A,R}x", `column 15: unexpected "x" after the end`},
+		{"px{(a<1:A,R}", `column 8: expected ")", found ":"`},
+		{"px{a#1:A,R}", `column 5: unexpected character '#'`},
+		{"px{1<2:A,R}", `column 4: a comparison needs one attribute and one number`},
+		{"px{a&1:A,R}", `column 5: unexpected character '&'`},
+	}
+	for _, tt := range tests {
+		_, err := NewWorkflow(tt.line)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) || err.Error() != tt.want {
+			t.Errorf("NewWorkflow(%q) = %v, want %v", tt.line, err, tt.want)
+		}
+	}
+	if _, err := NewPart("{x=1,x=2}"); err == nil || err.Error() != "column 6: attribute x is listed twice" {
+		t.Errorf("NewPart() = %v", err)
+	}
+	// a part outside the range would skip rules that the compiled tree pruned away, like
+	// x>4000 in in{x>4000:R,A}.
+	for _, line := range []string{"{x=5000}", "{x=0}"} {
+		if _, err := NewPart(line); err == nil || !strings.Contains(err.Error(), "column 4: rating") {
+			t.Errorf("NewPart(%q) = %v, want an out of range error", line, err)
+		}
+	}
+}
+
+// Split has to agree with Eval on every part, and the boxes it returns have to cover
+// the original box exactly once.
+func TestSplitMatchesEval(t *testing.T) {
+	rules := []string{
+		"x<3:A", "x<=3:A", "x>3:A", "x>=3:A", "x==3:A", "x!=3:A",
+		"x>=2 && y!=4:A", "x==1 || y<3:A", "(x<3 || y>4) && y!=6:A", "x<4 && (y==2 || y==5) || x==6:A",
+	}
+	attributes := []string{"x", "y"}
+	bounds := Bounds{utils.UniformBox(attributes, utils.Interval{Start: 1, End: 8})}
+	for _, ruleStr := range rules {
+		rule, err := NewRule(ruleStr)
+		if err != nil {
+			t.Fatalf("NewRule(%q) = %v", ruleStr, err)
+		}
+		pass, fail := rule.split(bounds)
+		for x := range 7 {
+			for y := range 7 {
+				part := Part{attributes: attributes, ratings: map[string]int{"x": x + 1, "y": y + 1}}
+				point := map[string]int64{"x": int64(x + 1), "y": int64(y + 1)}
+				inPass, inFail := 0, 0
+				for _, b := range pass {
+					if b.Contains(point) {
+						inPass++
+					}
+				}
+				for _, b := range fail {
+					if b.Contains(point) {
+						inFail++
+					}
+				}
+				want := 0
+				if rule.ShouldApply(part) {
+					want = 1
+				}
+				if inPass != want || inFail != 1-want {
+					t.Errorf("%s: part %v is in %d passing and %d failing boxes", ruleStr, part, inPass, inFail)
+				}
+			}
+		}
+	}
+}
+
+func TestSplitLargeValues(t *testing.T) {
+	bounds := Bounds{utils.UniformBox([]string{"x"}, utils.Interval{Start: 1, End: 8})}
+	tests := []struct {
+		op       string
+		wantPass int
+	}{
+		{"<", 7}, {"<=", 7}, {">", 0}, {">=", 0}, {"==", 0}, {"!=", 7},
+	}
+	for _, tt := range tests {
+		c := Comparison{Attribute: "x", Op: tt.op, Value: math.MaxInt64}
+		pass, fail := c.Split(bounds)
+		gotPass, gotFail := 0, 0
+		for _, b := range pass {
+			gotPass += b.NumPossibleValues()
+		}
+		for _, b := range fail {
+			gotFail += b.NumPossibleValues()
+		}
+		if gotPass != tt.wantPass || gotFail != 7-tt.wantPass {
+			t.Errorf("%v: Split() passes %d and fails %d, want %d and %d", c, gotPass, gotFail, tt.wantPass, 7-tt.wantPass)
+		}
+	}
+}
+
+func TestSplitUnknownAttribute(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Split() on an unknown attribute didn't panic")
+		}
+	}()
+	c := Comparison{Attribute: "y", Op: "<", Value: 3}
+	c.Split(Bounds{utils.UniformBox([]string{"x"}, utils.Interval{Start: 1, End: 8})})
+}
+
+func TestCustomAttributes(t *testing.T) {
+	workflows := mustParseWorkflows(t, "in{weight>=10 && color==2:A,R}")
+	part, err := NewPart("{weight=12,color=2}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := CompileWorkflows(workflows, Attributes([]Part{part}, workflows))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tree.Evaluate(part); got != "A" {
+		t.Errorf("Evaluate(%v) = %v, want A", part, got)
+	}
+	if got := tree.NumAccepted(); got != 3991 {
+		t.Errorf("NumAccepted() = %v, want 3991", got)
+	}
+	if _, err := CompileWorkflows(workflows, []string{"weight"}); err == nil || err.Error() != "column 18: workflow in reads unknown attribute color" {
+		t.Errorf("CompileWorkflows() = %v", err)
+	}
+
+	// workflows from a file also say which line the rule is on.
+	fname := filepath.Join(t.TempDir(), "workflows.txt")
+	if err := os.WriteFile(fname, []byte("in{x<5:aa,R}\naa{q<3:A,R}\n\n{x=1,q=2}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, workflows, err = ParseFile(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CompileWorkflows(workflows, []string{"x"}); err == nil || err.Error() != "line 2, column 4: workflow aa reads unknown attribute q" {
+		t.Errorf("CompileWorkflows() = %v", err)
+	}
+
+	// every part needs the attributes the rules read, or Eval and Split would disagree.
+	if err := os.WriteFile(fname, []byte("in{x<10:A,R}\n\n{x=1}\n{y=3}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ParseFile(fname); err == nil || !strings.HasSuffix(err.Error(), "line 4: part has no x rating, which the rules read") {
+		t.Errorf("ParseFile() = %v", err)
+	}
+}
@@ -1,3 +1,7 @@
 module github.com/nsanch/aoc/aoc2023/day2
 
-go 1.24.1
\ No newline at end of file
+go 1.24.1
+
+require github.com/nsanch/aoc/aoc2023/utils v0.0.0
+
+replace github.com/nsanch/aoc/aoc2023/utils => ../utils
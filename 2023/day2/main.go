@@ -1,13 +1,14 @@
-package main
+package day2
 
 import (
 	"bufio"
-	"fmt"
 	"log"
 	"os"
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/nsanch/aoc/aoc2023/utils"
 )
 
 type Batch struct {
@@ -105,10 +106,6 @@ func part2(fname string) int {
 	return result
 }
 
-func main() {
-	fmt.Println(part1("day2/day2-input-easy.txt"))
-	fmt.Println(part1("day2/day2-input.txt"))
-
-	fmt.Println(part2("day2/day2-input-easy.txt"))
-	fmt.Println(part2("day2/day2-input.txt"))
+func init() {
+	utils.RegisterPuzzle(2023, 2, part1, part2)
 }
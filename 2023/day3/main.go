@@ -1,31 +1,29 @@
-package main
+package day3
 
 import (
 	"bufio"
-	"fmt"
 	"log"
 	"os"
 	"strconv"
 	"strings"
 	"unicode"
+
+	"github.com/nsanch/aoc/aoc2023/utils"
 )
 
 type Schematic struct {
-	grid [][]rune
-}
-
-type Position struct {
-	x int
-	y int
+	grid utils.Grid[rune]
 }
 
+// the real input has a row with an extra character, so this can't use utils.ReadGridFromFile,
+// which rejects ragged grids.
 func ReadFile(fname string) Schematic {
 	file, err := os.Open(fname)
 	if err != nil {
 		log.Fatal(err)
 	}
 	scanner := bufio.NewScanner(file)
-	grid := make([][]rune, 0)
+	grid := make(utils.Grid[rune], 0)
 	for scanner.Scan() {
 		t := strings.TrimSpace(scanner.Text())
 		row := make([]rune, 0)
@@ -37,26 +35,31 @@ func ReadFile(fname string) Schematic {
 	return Schematic{grid: grid}
 }
 
-func hasNeighbor(s Schematic, startPos Position, numCharacters int) bool {
-	// look at any character neighboring the part number, including diagonals,
-	// so it's like a box surrounding the number. ignore the number itself and any '.' characters.
-	for x := startPos.x - 1; x <= startPos.x+numCharacters; x++ {
-		for y := startPos.y - 1; y <= startPos.y+1; y++ {
-			if y >= 0 && y < len(s.grid) && x >= 0 && x < len(s.grid[y]) {
-				if x >= startPos.x && x < startPos.x+numCharacters && y == startPos.y {
-					continue
-				}
-				if s.grid[y][x] != '.' && !unicode.IsDigit(s.grid[y][x]) {
-					//fmt.Printf("found neighbor %c at %d,%d relative to %d,%d+%d\n", s.grid[y][x], x, y, startPos.x, startPos.y, numCharacters)
-					return true
-				}
+// symbolNeighbors returns each symbol touching the part number, including diagonally.
+// it ignores the number itself and any '.' characters.
+func symbolNeighbors(s Schematic, startPos utils.Position, numCharacters int) []utils.Position {
+	var ret []utils.Position
+	seen := make(map[utils.Position]bool)
+	for i := range numCharacters {
+		for _, pos := range s.grid.Neighbors8(startPos.Step(utils.East, i)) {
+			if seen[pos] || (pos.Y == startPos.Y && pos.X >= startPos.X && pos.X < startPos.X+numCharacters) {
+				continue
+			}
+			seen[pos] = true
+			if r := s.grid.ItemAt(pos); r != '.' && !unicode.IsDigit(r) {
+				//fmt.Printf("found neighbor %c at %v relative to %v+%d\n", r, pos, startPos, numCharacters)
+				ret = append(ret, pos)
 			}
 		}
 	}
-	return false
+	return ret
 }
 
-func checkPartNumber(s Schematic, number_str []rune, startPos Position) int {
+func hasNeighbor(s Schematic, startPos utils.Position, numCharacters int) bool {
+	return len(symbolNeighbors(s, startPos, numCharacters)) > 0
+}
+
+func checkPartNumber(s Schematic, number_str []rune, startPos utils.Position) int {
 	number, err := strconv.Atoi(string(number_str))
 	if err != nil {
 		log.Fatal(err)
@@ -73,11 +76,11 @@ func findPartNumbers(s Schematic) []int {
 	for y, row := range s.grid {
 		//fmt.Printf("ROW %d\n", y)
 		var number_str []rune
-		var startPos Position
+		var startPos utils.Position
 		for x, r := range row {
 			if unicode.IsDigit(r) {
 				if number_str == nil {
-					startPos = Position{x: x, y: y}
+					startPos = utils.Position{X: x, Y: y}
 				}
 				number_str = append(number_str, r)
 			} else if len(number_str) > 0 {
@@ -111,33 +114,21 @@ func part1(fname string) int {
 // PART 2
 
 type PartAndNeighbor struct {
-	neighborPosition Position
+	neighborPosition utils.Position
 	partNumber       int
 }
 
-func getNeighbors(s Schematic, partNumber int, startPos Position, numCharacters int) []PartAndNeighbor {
+func getNeighbors(s Schematic, partNumber int, startPos utils.Position, numCharacters int) []PartAndNeighbor {
 	var ret []PartAndNeighbor
-	// look at any character neighboring the part number, including diagonals,
-	// so it's like a box surrounding the number. ignore the number itself and any '.' characters.
-	for x := startPos.x - 1; x <= startPos.x+numCharacters; x++ {
-		for y := startPos.y - 1; y <= startPos.y+1; y++ {
-			if y >= 0 && y < len(s.grid) && x >= 0 && x < len(s.grid[y]) {
-				if x >= startPos.x && x < startPos.x+numCharacters && y == startPos.y {
-					continue
-				}
-				if s.grid[y][x] != '.' && !unicode.IsDigit(s.grid[y][x]) {
-					//fmt.Printf("found neighbor %c at %d,%d relative to %d,%d+%d\n", s.grid[y][x], x, y, startPos.x, startPos.y, numCharacters)
-					if s.grid[y][x] == '*' {
-						ret = append(ret, PartAndNeighbor{neighborPosition: Position{x: x, y: y}, partNumber: partNumber})
-					}
-				}
-			}
+	for _, pos := range symbolNeighbors(s, startPos, numCharacters) {
+		if s.grid.ItemAt(pos) == '*' {
+			ret = append(ret, PartAndNeighbor{neighborPosition: pos, partNumber: partNumber})
 		}
 	}
 	return ret
 }
 
-func getPartNumberNeighbors(s Schematic, number_str []rune, startPos Position) []PartAndNeighbor {
+func getPartNumberNeighbors(s Schematic, number_str []rune, startPos utils.Position) []PartAndNeighbor {
 	partNum, err := strconv.Atoi(string(number_str))
 	if err != nil {
 		log.Fatal(err)
@@ -150,11 +141,11 @@ func findSymbols(s Schematic) []PartAndNeighbor {
 	for y, row := range s.grid {
 		//fmt.Printf("ROW %d\n", y)
 		var number_str []rune
-		var startPos Position
+		var startPos utils.Position
 		for x, r := range row {
 			if unicode.IsDigit(r) {
 				if number_str == nil {
-					startPos = Position{x: x, y: y}
+					startPos = utils.Position{X: x, Y: y}
 				}
 				number_str = append(number_str, r)
 			} else if len(number_str) > 0 {
@@ -174,7 +165,7 @@ func findSymbols(s Schematic) []PartAndNeighbor {
 func Part2(fname string) int {
 	s := ReadFile(fname)
 	symbol_positions := findSymbols(s)
-	var m map[Position][]int = make(map[Position][]int)
+	var m map[utils.Position][]int = make(map[utils.Position][]int)
 	result := 0
 
 	for _, pos := range symbol_positions {
@@ -189,10 +180,6 @@ func Part2(fname string) int {
 	return result
 }
 
-func main() {
-	fmt.Println(part1("day3/day3-input-easy.txt"))
-	fmt.Println(part1("day3/day3-input.txt"))
-
-	fmt.Println(Part2("day3/day3-input-easy.txt"))
-	fmt.Println(Part2("day3/day3-input.txt"))
+func init() {
+	utils.RegisterPuzzle(2023, 3, part1, Part2)
 }
@@ -4,4 +4,4 @@ go 1.24.1
 
 require github.com/nsanch/aoc/aoc2023/utils v0.0.0
 
-replace github.com/nsanch/aoc/aoc2023/utils => ../utils
\ No newline at end of file
+replace github.com/nsanch/aoc/aoc2023/utils => ../utils
@@ -1,8 +1,7 @@
-package main
+package day4
 
 import (
 	"bufio"
-	"fmt"
 	"log"
 	"os"
 	"regexp"
@@ -67,8 +66,8 @@ func readFile(fname string) []Card {
 		winning_numbers := strings.Split(split_by_pipe[1], " ")
 		ret = append(ret, Card{
 			cardid:          cardid,
-			numbers:         utils.ConvertStringsToInts(card_numbers),
-			winning_numbers: utils.ConvertStringsToInts(winning_numbers)})
+			numbers:         utils.MustConvertStringsToInts(card_numbers),
+			winning_numbers: utils.MustConvertStringsToInts(winning_numbers)})
 	}
 	return ret
 }
@@ -79,7 +78,6 @@ func part1(fname string) int {
 	for _, card := range cards {
 		result += card.CardValue()
 	}
-	fmt.Println(result)
 	return result
 }
 
@@ -97,14 +95,9 @@ func part2(fname string) int {
 			num_copies_of_each_card[card.cardid+i] += num_copies_of_this_card
 		}
 	}
-	fmt.Println(total_number_of_cards)
 	return total_number_of_cards
 }
 
-func main() {
-	part1("day4-input-easy.txt")
-	part1("day4-input.txt")
-
-	part2("day4-input-easy.txt")
-	part2("day4-input.txt")
+func init() {
+	utils.RegisterPuzzle(2023, 4, part1, part2)
 }
@@ -1,8 +1,7 @@
-package main
+package day5
 
 import (
 	"bufio"
-	"fmt"
 	"log"
 	"os"
 	"regexp"
@@ -45,6 +44,27 @@ func (inputMap InputMap) mapOutputToInput(output int) int {
 	return output
 }
 
+// mapRangesToOutputs maps a whole set of inputs at once, splitting it wherever it
+// crosses the edge of a defined range.
+func (inputMap InputMap) mapRangesToOutputs(inputs utils.IntervalSet) utils.IntervalSet {
+	outputs := utils.NewIntervalSet()
+	mapped := utils.NewIntervalSet()
+	for _, definedRange := range inputMap.definedRanges {
+		source := utils.NewIntervalSet(utils.Interval{
+			Start: int64(definedRange.sourceRangeStart),
+			End:   int64(definedRange.sourceRangeEnd)})
+		offset := int64(definedRange.destinationRangeStart - definedRange.sourceRangeStart)
+		shifted := make([]utils.Interval, 0)
+		for overlap := range inputs.Intersection(source).All() {
+			shifted = append(shifted, utils.Interval{Start: overlap.Start + offset, End: overlap.End + offset})
+		}
+		outputs = outputs.Union(utils.NewIntervalSet(shifted...))
+		mapped = mapped.Union(source)
+	}
+	// anything not covered by a defined range maps to itself.
+	return outputs.Union(inputs.Difference(mapped))
+}
+
 type CategoryAndLocation struct {
 	category string
 	location int
@@ -92,7 +112,7 @@ func parseFile(name string) *Almanac {
 	scanner.Scan()
 	seedsLine := scanner.Text()
 	seeds_re := regexp.MustCompile(`\d+`)
-	desiredSeeds := utils.ConvertStringsToInts(seeds_re.FindAllString(seedsLine, -1))
+	desiredSeeds := utils.MustConvertStringsToInts(seeds_re.FindAllString(seedsLine, -1))
 	for _, seed := range desiredSeeds {
 		almanac.desiredSeeds = append(almanac.desiredSeeds, CategoryAndLocation{
 			category: "seed", location: seed})
@@ -109,7 +129,7 @@ func parseFile(name string) *Almanac {
 			if line == "" {
 				break
 			}
-			lineNumbers := utils.ConvertStringsToInts(strings.Split(strings.TrimSpace(line), " "))
+			lineNumbers := utils.MustConvertStringsToInts(strings.Split(strings.TrimSpace(line), " "))
 			definedRanges = append(definedRanges, InputMapRange{
 				destinationRangeStart: lineNumbers[0],
 				sourceRangeStart:      lineNumbers[1],
@@ -169,10 +189,46 @@ func navigateBackToSeedFromLocation(almanac *Almanac, startingPoints []CategoryA
 func part1(fname string) int {
 	almanac := parseFile(fname)
 	locations := navigateMapsToLocations(almanac, almanac.desiredSeeds)
-	fmt.Println(locations)
+	//fmt.Println(locations)
 	return slices.Min(locations)
 }
 
+type CategoryAndRanges struct {
+	category string
+	ranges   utils.IntervalSet
+}
+
+func navigateRangesToLocations(almanac *Almanac, seeds utils.IntervalSet) utils.IntervalSet {
+	knownData := []CategoryAndRanges{{category: "seed", ranges: seeds}}
+	results := utils.NewIntervalSet()
+	for len(knownData) > 0 {
+		current := knownData[0]
+		knownData = knownData[1:]
+		for _, inputMap := range almanac.inputMaps[current.category] {
+			output := CategoryAndRanges{
+				category: inputMap.destinationName,
+				ranges:   inputMap.mapRangesToOutputs(current.ranges)}
+			if output.category == "location" {
+				results = results.Union(output.ranges)
+			} else {
+				knownData = append(knownData, output)
+			}
+		}
+	}
+	return results
+}
+
+// seedRanges reads the seeds line as pairs of start and length.
+func (almanac Almanac) seedRanges() utils.IntervalSet {
+	intervals := make([]utils.Interval, 0)
+	for pairStart := 0; pairStart+1 < len(almanac.desiredSeeds); pairStart += 2 {
+		start := almanac.desiredSeeds[pairStart].location
+		length := almanac.desiredSeeds[pairStart+1].location
+		intervals = append(intervals, utils.Interval{Start: int64(start), End: int64(start + length)})
+	}
+	return utils.NewIntervalSet(intervals...)
+}
+
 /*
 	func navigateFromOneSeedLocationToBest(almanac *Almanac, seed CategoryAndLocation) int {
 		locations := navigateMapsToLocations(almanac, []CategoryAndLocation{seed})
@@ -183,6 +239,18 @@ func part1(fname string) int {
 	}
 */
 func part2(fname string) int {
+	almanac := parseFile(fname)
+	locations := navigateRangesToLocations(almanac, almanac.seedRanges())
+	best, ok := locations.Min()
+	if !ok {
+		log.Fatalf("no seeds reach a location in %s", fname)
+	}
+	return int(best)
+}
+
+// part2BruteForce walks back from every location in turn until it reaches one of the
+// seeds. It takes minutes on the real input but is handy for checking part2.
+func part2BruteForce(fname string) int {
 	almanac := parseFile(fname)
 	for currLocation := 1; true; currLocation++ {
 		start := CategoryAndLocation{category: "location", location: currLocation}
@@ -193,23 +261,19 @@ func part2(fname string) int {
 				end := start + almanac.desiredSeeds[pairStart+1].location
 				if result.location >= start &&
 					result.location < end {
-					fmt.Printf("Started from %d and got to seed %d\n", currLocation, result.location)
+					//fmt.Printf("Started from %d and got to seed %d\n", currLocation, result.location)
 					return currLocation
 				}
 			}
 		}
-		if currLocation%1000000 == 0 {
-			fmt.Printf("Continuing on after %d\n", currLocation)
-		}
+		//if currLocation%1000000 == 0 {
+		//	fmt.Printf("Continuing on after %d\n", currLocation)
+		//}
 	}
 	// shouldn't happen since the above is an infinite loop, but go wants a return here.
 	return 0
 }
 
-func main() {
-	fmt.Println(part1("day5-input-easy.txt"))
-	fmt.Println(part1("day5-input.txt"))
-
-	fmt.Println(part2("day5-input-easy.txt"))
-	fmt.Println(part2("day5-input.txt"))
+func init() {
+	utils.RegisterPuzzle(2023, 5, part1, part2)
 }
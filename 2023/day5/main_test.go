@@ -0,0 +1,31 @@
+package day5
+
+import (
+	"testing"
+
+	"github.com/nsanch/aoc/aoc2023/utils"
+)
+
+func TestPart2MatchesBruteForce(t *testing.T) {
+	if got, want := part2("day5-input-easy.txt"), part2BruteForce("day5-input-easy.txt"); got != want {
+		t.Errorf("part2() = %v, want %v", got, want)
+	}
+}
+
+func TestMapRangesToOutputs(t *testing.T) {
+	almanac := parseFile("day5-input-easy.txt")
+	for _, inputMaps := range almanac.inputMaps {
+		for _, inputMap := range inputMaps {
+			inputs := utils.NewIntervalSet(utils.Interval{Start: 0, End: 120})
+			outputs := inputMap.mapRangesToOutputs(inputs)
+			if outputs.Len() != inputs.Len() {
+				t.Errorf("%s-to-%s mapped %v to %v, which has a different size", inputMap.sourceName, inputMap.destinationName, inputs, outputs)
+			}
+			for input := range inputs.Values() {
+				if output := inputMap.mapInputToOutput(int(input)); !outputs.Contains(int64(output)) {
+					t.Errorf("%s-to-%s maps %d to %d, which isn't in %v", inputMap.sourceName, inputMap.destinationName, input, output, outputs)
+				}
+			}
+		}
+	}
+}
@@ -1,8 +1,7 @@
-package main
+package day6
 
 import (
 	"bufio"
-	"fmt"
 	"log"
 	"os"
 	"regexp"
@@ -35,8 +34,8 @@ func parseFile(name string, ignoreSpaces bool) []Race {
 		distanceLine = strings.ReplaceAll(distanceLine, " ", "")
 	}
 	numbersRE := regexp.MustCompile(`\d+`)
-	times := utils.ConvertStringsToInts(numbersRE.FindAllString(timeLine, -1))
-	distances := utils.ConvertStringsToInts(numbersRE.FindAllString(distanceLine, -1))
+	times := utils.MustConvertStringsToInts(numbersRE.FindAllString(timeLine, -1))
+	distances := utils.MustConvertStringsToInts(numbersRE.FindAllString(distanceLine, -1))
 	if len(times) != len(distances) {
 		log.Fatalf("Mismatched number of times and distances. %d vs %d", len(times), len(distances))
 	}
@@ -75,10 +74,6 @@ func part2(fname string) int {
 	return findNumberOfWinningSolutions(races[0])
 }
 
-func main() {
-	fmt.Println(part1("day6-input-easy.txt"))
-	fmt.Println(part1("day6-input.txt"))
-
-	fmt.Println(part2("day6-input-easy.txt"))
-	fmt.Println(part2("day6-input.txt"))
+func init() {
+	utils.RegisterPuzzle(2023, 6, part1, part2)
 }
@@ -2,4 +2,6 @@ module github.com/nsanch/aoc/aoc2023/day7
 
 go 1.24.1
 
+require github.com/nsanch/aoc/aoc2023/utils v0.0.0
+
 replace github.com/nsanch/aoc/aoc2023/utils => ../utils
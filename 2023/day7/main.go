@@ -1,14 +1,15 @@
-package main
+package day7
 
 import (
 	"bufio"
 	"cmp"
-	"fmt"
 	"log"
 	"os"
 	"slices"
 	"strconv"
 	"strings"
+
+	"github.com/nsanch/aoc/aoc2023/utils"
 )
 
 type Card int
@@ -363,10 +364,6 @@ func part2(fname string) int {
 	return result
 }
 
-func main() {
-	fmt.Println(part1("day7-input-easy.txt"))
-	fmt.Println(part1("day7-input.txt"))
-
-	fmt.Println(part2("day7-input-easy.txt"))
-	fmt.Println(part2("day7-input.txt"))
+func init() {
+	utils.RegisterPuzzle(2023, 7, part1, part2)
 }
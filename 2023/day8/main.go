@@ -1,4 +1,4 @@
-package main
+package day8
 
 import (
 	"bufio"
@@ -105,11 +105,6 @@ func part2(fname string) int {
 	return navigateMapConcurrently(nodeMap, instructions)
 }
 
-func main() {
-	fmt.Println(part1("day8-input-easy.txt"))
-	fmt.Println(part1("day8-input-easy2.txt"))
-	fmt.Println(part1("day8-input.txt"))
-
-	fmt.Println(part2("day8-input-easy3.txt"))
-	fmt.Println(part2("day8-input.txt"))
+func init() {
+	utils.RegisterPuzzle(2023, 8, part1, part2)
 }
@@ -1,8 +1,7 @@
-package main
+package day9
 
 import (
 	"bufio"
-	"fmt"
 	"log"
 	"os"
 	"strings"
@@ -54,7 +53,7 @@ func parseFile(fname string) []Sequence {
 	var ret []Sequence
 	for scanner.Scan() {
 		line := scanner.Text()
-		sequence := utils.ConvertStringsToInts(strings.Fields(line))
+		sequence := utils.MustConvertStringsToInts(strings.Fields(line))
 		ret = append(ret, sequence)
 	}
 	return ret
@@ -78,10 +77,6 @@ func part2(fname string) int {
 	return result
 }
 
-func main() {
-	fmt.Println(part1("day9-input-easy.txt"))
-	fmt.Println(part1("day9-input.txt"))
-
-	fmt.Println(part2("day9-input-easy.txt"))
-	fmt.Println(part2("day9-input.txt"))
+func init() {
+	utils.RegisterPuzzle(2023, 9, part1, part2)
 }
@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"iter"
+	"slices"
+)
+
+// ShortestPaths remembers every way of reaching the goals at the shortest distance,
+// unlike FindDistanceAndPath which keeps a single predecessor per node.
+type ShortestPaths[T comparable] struct {
+	Distance int
+	// Ends are the goals reached at Distance.
+	Ends  []T
+	preds map[T][]T
+}
+
+// FindAllShortestPaths is FindDistanceAndPath, except that it keeps every tied
+// predecessor. Edge costs must be positive, otherwise the tied paths could loop.
+func (graph *Graph[T]) FindAllShortestPaths(froms []T, ends []T) (*ShortestPaths[T], error) {
+	return FindAllShortestPathsFunc(froms, graph.Neighbors, func(node T) bool { return slices.Contains(ends, node) })
+}
+
+// FindAllShortestPathsFunc is FindAllShortestPaths over an implicit graph, like
+// FindDistanceAndPathFunc.
+func FindAllShortestPathsFunc[T comparable](froms []T, neighbors func(T) iter.Seq[Neighbor[T]], isGoal func(T) bool) (*ShortestPaths[T], error) {
+	toVisit := NewIndexedHeap[T]()
+	distances := make(map[T]int)
+	for _, from := range froms {
+		toVisit.Push(from, 0)
+		distances[from] = 0
+	}
+	ret := &ShortestPaths[T]{Distance: -1, preds: make(map[T][]T)}
+
+	for toVisit.Len() > 0 {
+		currPos, currDistance, _ := toVisit.PopMin()
+		// once a goal is found, keep going only until everything at the same distance
+		// has been seen, since those can be other goals or other ways to the same one.
+		if ret.Distance >= 0 && currDistance > ret.Distance {
+			break
+		}
+		if isGoal(currPos) {
+			ret.Distance = currDistance
+			ret.Ends = append(ret.Ends, currPos)
+		}
+
+		for neighbor := range neighbors(currPos) {
+			prevCost, hasPrevCost := distances[neighbor.Value]
+			newCostToNeighbor := currDistance + neighbor.Cost
+			if hasPrevCost && prevCost < newCostToNeighbor {
+				continue
+			}
+			if hasPrevCost && prevCost == newCostToNeighbor {
+				ret.preds[neighbor.Value] = append(ret.preds[neighbor.Value], currPos)
+				continue
+			}
+
+			ret.preds[neighbor.Value] = []T{currPos}
+			distances[neighbor.Value] = newCostToNeighbor
+			if !toVisit.DecreaseKey(neighbor.Value, newCostToNeighbor) {
+				toVisit.Push(neighbor.Value, newCostToNeighbor)
+			}
+		}
+	}
+	if ret.Distance < 0 {
+		return nil, ErrNoPath
+	}
+	return ret, nil
+}
+
+// Paths yields every shortest path, from one of the froms to one of the Ends. There
+// can be exponentially many, so they're only built as they're asked for. The slice
+// is reused between paths; clone it to keep it.
+func (sp *ShortestPaths[T]) Paths() iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		// walk backwards from each end, so path is reversed until it's yielded.
+		path := make([]T, 0)
+		out := make([]T, 0)
+		var walk func(node T) bool
+		walk = func(node T) bool {
+			path = append(path, node)
+			defer func() { path = path[:len(path)-1] }()
+			preds := sp.preds[node]
+			if len(preds) == 0 {
+				out = append(out[:0], path...)
+				slices.Reverse(out)
+				return yield(out)
+			}
+			for _, pred := range preds {
+				if !walk(pred) {
+					return false
+				}
+			}
+			return true
+		}
+		for _, end := range sp.Ends {
+			if !walk(end) {
+				return
+			}
+		}
+	}
+}
+
+// Count returns how many distinct shortest paths there are, without listing them.
+func (sp *ShortestPaths[T]) Count() int {
+	counts := make(map[T]int)
+	var count func(node T) int
+	count = func(node T) int {
+		if c, ok := counts[node]; ok {
+			return c
+		}
+		preds := sp.preds[node]
+		c := 0
+		if len(preds) == 0 {
+			c = 1
+		}
+		for _, pred := range preds {
+			c += count(pred)
+		}
+		counts[node] = c
+		return c
+	}
+	total := 0
+	for _, end := range sp.Ends {
+		total += count(end)
+	}
+	return total
+}
+
+// Nodes returns the set of nodes that lie on at least one shortest path.
+func (sp *ShortestPaths[T]) Nodes() map[T]bool {
+	ret := make(map[T]bool)
+	toVisit := slices.Clone(sp.Ends)
+	for len(toVisit) > 0 {
+		curr := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+		if ret[curr] {
+			continue
+		}
+		ret[curr] = true
+		toVisit = append(toVisit, sp.preds[curr]...)
+	}
+	return ret
+}
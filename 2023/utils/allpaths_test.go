@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFindAllShortestPaths(t *testing.T) {
+	graph := make(Graph[string])
+	graph.AddEdge("a", "b", 1)
+	graph.AddEdge("a", "c", 1)
+	graph.AddEdge("b", "d", 1)
+	graph.AddEdge("c", "d", 1)
+	graph.AddEdge("a", "d", 3)
+	graph.AddEdge("d", "e", 1)
+	graph.AddEdge("a", "x", 1)
+	graph.AddEdge("x", "e", 5)
+
+	sp, err := graph.FindAllShortestPaths([]string{"a"}, []string{"e"})
+	if err != nil || sp.Distance != 3 {
+		t.Fatalf("FindAllShortestPaths() = %v, %v, want distance 3", sp, err)
+	}
+	var paths []string
+	for path := range sp.Paths() {
+		paths = append(paths, strings.Join(path, ""))
+	}
+	slices.Sort(paths)
+	if want := []string{"abde", "acde"}; !slices.Equal(paths, want) {
+		t.Errorf("Paths() = %v, want %v", paths, want)
+	}
+	if got := sp.Count(); got != 2 {
+		t.Errorf("Count() = %v, want 2", got)
+	}
+	nodes := slices.Sorted(maps.Keys(sp.Nodes()))
+	if want := []string{"a", "b", "c", "d", "e"}; !slices.Equal(nodes, want) {
+		t.Errorf("Nodes() = %v, want %v", nodes, want)
+	}
+}
+
+func TestFindAllShortestPathsGrid(t *testing.T) {
+	// every monotone path across an open grid is shortest.
+	grid := NewGrid(4, 4, 1)
+	graph := make(Graph[Position])
+	for pos := range grid.All() {
+		for _, next := range grid.Neighbors4(pos) {
+			graph.AddEdge(pos, next, grid.ItemAt(next))
+		}
+	}
+	froms := []Position{{X: 0, Y: 0}}
+	ends := []Position{{X: 3, Y: 3}, {X: 2, Y: 3}}
+	sp, err := graph.FindAllShortestPaths(froms, ends)
+	if err != nil || sp.Distance != 5 {
+		t.Fatalf("FindAllShortestPaths() = %v, %v, want distance 5", sp, err)
+	}
+	// (2,3) is the only end at distance 5, and there are C(5,2) ways to it.
+	if got := sp.Count(); got != 10 {
+		t.Errorf("Count() = %v, want 10", got)
+	}
+	numPaths := 0
+	for path := range sp.Paths() {
+		numPaths++
+		if len(path) != 6 || path[0] != froms[0] || path[5] != ends[1] {
+			t.Errorf("Paths() yielded %v", path)
+		}
+	}
+	if numPaths != 10 {
+		t.Errorf("Paths() yielded %d paths, want 10", numPaths)
+	}
+	if got := len(sp.Nodes()); got != 12 {
+		t.Errorf("len(Nodes()) = %v, want 12", got)
+	}
+
+	sp, _ = graph.FindAllShortestPaths(froms, []Position{{X: 3, Y: 0}, {X: 0, Y: 3}})
+	if got := sp.Count(); got != 2 || len(sp.Ends) != 2 {
+		t.Errorf("Count() with two ends at the same distance = %v (ends %v), want 2", got, sp.Ends)
+	}
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// An Answer is one known-good result, read from a day's dayN-answers.txt file. Each
+// non-blank line of that file has four whitespace-separated fields:
+//
+//	<day> <part> <input file> <expected value>
+//
+// and anything after a '#' is a comment.
+type Answer struct {
+	Day      int
+	Part     int
+	Input    string
+	Expected int
+}
+
+func (a Answer) String() string {
+	return fmt.Sprintf("day %d part %d %s = %d", a.Day, a.Part, a.Input, a.Expected)
+}
+
+func ParseAnswers(r io.Reader) ([]Answer, error) {
+	scanner := bufio.NewScanner(r)
+	ret := make([]Answer, 0)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("line %d: expected 4 fields, got %d", lineNum, len(fields))
+		}
+		day, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid day: %w", lineNum, err)
+		}
+		part, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid part: %w", lineNum, err)
+		}
+		expected, err := strconv.Atoi(fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid expected value: %w", lineNum, err)
+		}
+		ret = append(ret, Answer{Day: day, Part: part, Input: fields[2], Expected: expected})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func ReadAnswersFile(fname string) ([]Answer, error) {
+	file, err := os.Open(fname)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	answers, err := ParseAnswers(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fname, err)
+	}
+	return answers, nil
+}
+
+// Answers reads the puzzle's dayN-answers.txt file from Dir. A day without one has no
+// answers, which is also what happens when Dir is stale.
+func (p *Puzzle) Answers() ([]Answer, error) {
+	fname := filepath.Join(p.Dir, fmt.Sprintf("day%d-answers.txt", p.Day))
+	answers, err := ReadAnswersFile(fname)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range answers {
+		if a.Day != p.Day {
+			return nil, fmt.Errorf("%s: answer for day %d found in day %d's file", fname, a.Day, p.Day)
+		}
+	}
+	return answers, nil
+}
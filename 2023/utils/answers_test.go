@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseAnswers(t *testing.T) {
+	in := `# day part input expected
+17 1 day17-input-easy.txt 102
+
+17 2 day17-input.txt    1106 # slow
+`
+	want := []Answer{
+		{Day: 17, Part: 1, Input: "day17-input-easy.txt", Expected: 102},
+		{Day: 17, Part: 2, Input: "day17-input.txt", Expected: 1106},
+	}
+	got, err := ParseAnswers(strings.NewReader(in))
+	if err != nil || !slices.Equal(got, want) {
+		t.Errorf("ParseAnswers() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestParseAnswersErrors(t *testing.T) {
+	tests := []string{
+		"17 1 day17-input.txt",
+		"17 1 day17-input.txt abc",
+		"x 1 day17-input.txt 5",
+		"17 one day17-input.txt 5",
+	}
+	for _, in := range tests {
+		t.Run(in, func(t *testing.T) {
+			if got, err := ParseAnswers(strings.NewReader(in)); err == nil {
+				t.Errorf("ParseAnswers(%q) = %v, want an error", in, got)
+			}
+		})
+	}
+}
@@ -22,7 +22,7 @@ func Test_GetInteriorPoints(t *testing.T) {
 			path: []Position{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 1}, {X: 4, Y: 2}, {X: 3, Y: 2}, {X: 3, Y: 3}, {X: 3, Y: 4}, {X: 2, Y: 4}, {X: 1, Y: 4}, {X: 0, Y: 4}, {X: 0, Y: 3}, {X: 0, Y: 2}, {X: 0, Y: 1}},
 			want: 7}}
 
-	for _, tt := range tests[0:1] {
+	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got := GetInteriorPoints(tt.path)
 			if got.NumPoints() != tt.want {
@@ -31,3 +31,57 @@ func Test_GetInteriorPoints(t *testing.T) {
 		})
 	}
 }
+
+func TestSparseGridNegativeBounds(t *testing.T) {
+	grid := NewSparseGrid()
+	grid.Set(Position{X: -2, Y: -1}, '#')
+	grid.Set(Position{X: 1, Y: 0}, '#')
+	if minPos, maxPos := grid.Bounds(); minPos != (Position{X: -2, Y: -1}) || maxPos != (Position{X: 1, Y: 0}) {
+		t.Errorf("Bounds() = %v, %v, want (-2, -1), (1, 0)", minPos, maxPos)
+	}
+	if got, want := grid.String(), "#...\n...#\n"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func Test_GetInteriorPointsNegativeCoordinates(t *testing.T) {
+	// the same square as case "1" above, shifted so every coordinate is negative.
+	path := []Position{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 1}, {X: 2, Y: 2}, {X: 1, Y: 2}, {X: 0, Y: 2}, {X: 0, Y: 1}}
+	for i := range path {
+		path[i] = path[i].Sub(Position{X: 10, Y: 5})
+	}
+	if got := GetInteriorPoints(path).NumPoints(); got != 1 {
+		t.Errorf("GetInteriorPoints() = %v, want 1", got)
+	}
+}
+
+func Test_GetInteriorPointsMatchesPolygon(t *testing.T) {
+	// the day 18 example trench, given by its corners and by every cell along it.
+	moves := []PolygonMove{
+		{East, 6}, {South, 5}, {West, 2}, {South, 2}, {East, 2}, {South, 2}, {West, 5},
+		{North, 2}, {West, 1}, {North, 2}, {East, 2}, {North, 3}, {West, 2}, {North, 2},
+	}
+	polygon := MustNewPolygonFromMoves(Position{}, moves)
+	cells := []Position{{}}
+	for _, move := range moves {
+		for range move.Length {
+			cells = append(cells, cells[len(cells)-1].Step(move.Direction, 1))
+		}
+	}
+	want := polygon.LatticeInteriorPoints()
+	for name, path := range map[string][]Position{"corners": polygon.Vertices, "cells": cells} {
+		got := GetInteriorPoints(path)
+		if got.NumPoints() != want {
+			t.Errorf("GetInteriorPoints(%s) = %v, want %v", name, got.NumPoints(), want)
+		}
+		for _, p := range got.EnumerateAllPointsSlow() {
+			if !polygon.ContainsPoint(p, EvenOdd) {
+				t.Errorf("GetInteriorPoints(%s) includes %v, which is outside", name, p)
+			}
+		}
+		// one range per run of interior cells in a row.
+		if len(got.ranges) != 8 {
+			t.Errorf("GetInteriorPoints(%s) returned %d ranges, want 8", name, len(got.ranges))
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package utils
+
+import (
+	"fmt"
+	"iter"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Box is an axis-aligned box of integer points with named axes, holding a half-open
+// Interval of values along each one. Axes are kept sorted by name. Operations return
+// new boxes and never modify their inputs.
+type Box struct {
+	axes   []string
+	ranges []Interval
+}
+
+func NewBox(ranges map[string]Interval) Box {
+	axes := slices.Sorted(maps.Keys(ranges))
+	b := Box{axes: axes, ranges: make([]Interval, len(axes))}
+	for i, axis := range axes {
+		b.ranges[i] = ranges[axis]
+	}
+	return b
+}
+
+// UniformBox makes a box with the same bounds along every axis.
+func UniformBox(axes []string, bounds Interval) Box {
+	ranges := make(map[string]Interval, len(axes))
+	for _, axis := range axes {
+		ranges[axis] = bounds
+	}
+	return NewBox(ranges)
+}
+
+func (b Box) Axes() []string {
+	return slices.Clone(b.axes)
+}
+
+func (b Box) Dims() int {
+	return len(b.axes)
+}
+
+func (b Box) axisIndex(axis string) (int, bool) {
+	return slices.BinarySearch(b.axes, axis)
+}
+
+func (b Box) mustAxisIndex(axis string) int {
+	i, ok := b.axisIndex(axis)
+	if !ok {
+		log.Panicf("box %v has no axis %q", b, axis)
+	}
+	return i
+}
+
+// Range returns the box's bounds along axis, or false if it has no such axis.
+func (b Box) Range(axis string) (Interval, bool) {
+	i, ok := b.axisIndex(axis)
+	if !ok {
+		return Interval{}, false
+	}
+	return b.ranges[i], true
+}
+
+func (b Box) Empty() bool {
+	return slices.ContainsFunc(b.ranges, Interval.Empty)
+}
+
+// Volume returns the number of integer points in the box.
+func (b Box) Volume() int64 {
+	ret := int64(1)
+	for _, r := range b.ranges {
+		ret *= r.Len()
+	}
+	return ret
+}
+
+// Contains checks whether point, which gives a value for each axis, is in the box.
+func (b Box) Contains(point map[string]int64) bool {
+	for i, axis := range b.axes {
+		v, ok := point[axis]
+		if !ok || !b.ranges[i].Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// With returns a copy of the box with its bounds along axis replaced.
+func (b Box) With(axis string, bounds Interval) Box {
+	i := b.mustAxisIndex(axis)
+	ret := Box{axes: b.axes, ranges: slices.Clone(b.ranges)}
+	ret.ranges[i] = bounds
+	return ret
+}
+
+// SplitAt cuts the box in two along axis: the points below threshold and the points at
+// or above it. Either half can be empty.
+func (b Box) SplitAt(axis string, threshold int64) (Box, Box) {
+	r := b.ranges[b.mustAxisIndex(axis)]
+	below := b.With(axis, Interval{Start: r.Start, End: min(r.End, threshold)})
+	above := b.With(axis, Interval{Start: max(r.Start, threshold), End: r.End})
+	return below, above
+}
+
+// Intersection returns the points in both boxes, which must have the same axes.
+func (b Box) Intersection(other Box) Box {
+	if !slices.Equal(b.axes, other.axes) {
+		log.Panicf("can't intersect boxes with axes %v and %v", b.axes, other.axes)
+	}
+	ret := Box{axes: b.axes, ranges: make([]Interval, len(b.ranges))}
+	for i, r := range b.ranges {
+		o := other.ranges[i]
+		ret.ranges[i] = Interval{Start: max(r.Start, o.Start), End: min(r.End, o.End)}
+	}
+	return ret
+}
+
+func (b Box) Equal(other Box) bool {
+	return slices.Equal(b.axes, other.axes) && slices.Equal(b.ranges, other.ranges)
+}
+
+func (b Box) String() string {
+	parts := make([]string, len(b.axes))
+	for i, axis := range b.axes {
+		parts[i] = fmt.Sprintf("%s=%v", axis, b.ranges[i])
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// BoxSet is a union of boxes that all have the same axes. The boxes may overlap.
+type BoxSet struct {
+	boxes []Box
+}
+
+func NewBoxSet(boxes ...Box) BoxSet {
+	var s BoxSet
+	for _, b := range boxes {
+		s.Add(b)
+	}
+	return s
+}
+
+// Add includes b in the set. Empty boxes are dropped.
+func (s *BoxSet) Add(b Box) {
+	if len(s.boxes) > 0 && !slices.Equal(s.boxes[0].axes, b.axes) {
+		log.Panicf("can't add box with axes %v to a set with axes %v", b.axes, s.boxes[0].axes)
+	}
+	if !b.Empty() {
+		s.boxes = append(s.boxes, b)
+	}
+}
+
+// All yields the boxes in the order they were added.
+func (s BoxSet) All() iter.Seq[Box] {
+	return slices.Values(s.boxes)
+}
+
+func (s BoxSet) Len() int {
+	return len(s.boxes)
+}
+
+func (s BoxSet) Contains(point map[string]int64) bool {
+	return slices.ContainsFunc(s.boxes, func(b Box) bool { return b.Contains(point) })
+}
+
+// Volume returns the number of integer points in at least one of the boxes, counting
+// each point once however many boxes it's in.
+func (s BoxSet) Volume() int64 {
+	return unionVolume(s.boxes, 0)
+}
+
+// unionVolume sweeps along dimension dim: between consecutive box edges the same boxes
+// cover every slice, so each slab's volume is its width times the union of those boxes'
+// remaining dimensions.
+func unionVolume(boxes []Box, dim int) int64 {
+	if len(boxes) == 0 {
+		return 0
+	}
+	if dim == boxes[0].Dims() {
+		return 1
+	}
+	if dim == boxes[0].Dims()-1 {
+		intervals := make([]Interval, len(boxes))
+		for i, b := range boxes {
+			intervals[i] = b.ranges[dim]
+		}
+		return NewIntervalSet(intervals...).Len()
+	}
+
+	edges := make([]int64, 0, 2*len(boxes))
+	for _, b := range boxes {
+		edges = append(edges, b.ranges[dim].Start, b.ranges[dim].End)
+	}
+	slices.Sort(edges)
+	edges = slices.Compact(edges)
+
+	total := int64(0)
+	for i := 0; i+1 < len(edges); i++ {
+		slab := Interval{Start: edges[i], End: edges[i+1]}
+		covering := make([]Box, 0)
+		for _, b := range boxes {
+			if b.ranges[dim].Start <= slab.Start && slab.End <= b.ranges[dim].End {
+				covering = append(covering, b)
+			}
+		}
+		total += slab.Len() * unionVolume(covering, dim+1)
+	}
+	return total
+}
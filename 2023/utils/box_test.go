@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBox(t *testing.T) {
+	b := NewBox(map[string]Interval{"x": iv(0, 10), "y": iv(5, 8)})
+	if got := b.Volume(); got != 30 {
+		t.Errorf("Volume() = %v, want 30", got)
+	}
+	if got, want := b.String(), "{x=[0, 10), y=[5, 8)}"; got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+	below, above := b.SplitAt("x", 3)
+	if below.Volume() != 9 || above.Volume() != 21 {
+		t.Errorf("SplitAt() = %v, %v", below, above)
+	}
+	below, above = b.SplitAt("y", 20)
+	if !below.Equal(b) || !above.Empty() {
+		t.Errorf("SplitAt() past the end = %v, %v", below, above)
+	}
+	if !b.Contains(map[string]int64{"x": 3, "y": 7}) || b.Contains(map[string]int64{"x": 3, "y": 8}) || b.Contains(map[string]int64{"x": 3}) {
+		t.Error("Contains() is wrong")
+	}
+	overlap := b.Intersection(NewBox(map[string]Interval{"x": iv(8, 20), "y": iv(0, 6)}))
+	if got, want := overlap.String(), "{x=[8, 10), y=[5, 6)}"; got != want {
+		t.Errorf("Intersection() = %v, want %v", got, want)
+	}
+	if r, ok := UniformBox([]string{"a", "b"}, iv(1, 4001)).Range("b"); !ok || r != iv(1, 4001) {
+		t.Errorf("Range() = %v, %v", r, ok)
+	}
+}
+
+// compare the union volume against counting points one by one on random overlapping boxes.
+func TestBoxSetVolume(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	axes := []string{"x", "y", "z"}
+	for range 200 {
+		var s BoxSet
+		for range r.Intn(6) {
+			ranges := make(map[string]Interval)
+			for _, axis := range axes {
+				start := int64(r.Intn(10))
+				ranges[axis] = iv(start, start+int64(r.Intn(6)))
+			}
+			s.Add(NewBox(ranges))
+		}
+		want := int64(0)
+		for x := range int64(16) {
+			for y := range int64(16) {
+				for z := range int64(16) {
+					if s.Contains(map[string]int64{"x": x, "y": y, "z": z}) {
+						want++
+					}
+				}
+			}
+		}
+		if got := s.Volume(); got != want {
+			t.Fatalf("Volume() = %v, want %v for %v", got, want, s.boxes)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"slices"
+)
+
+// A Contraction is a graph with its corridors squashed into single edges between
+// junctions, remembering what each edge stands for so paths can be expanded again.
+type Contraction[T comparable] struct {
+	Graph Graph[T]
+	// Corridors maps each edge of Graph to the original nodes along it, both ends included.
+	// Corridors between the same junctions with the same cost share a single edge, so
+	// each edge can stand for several of them, listed in the order they were found.
+	Corridors map[Edge[T]][][]T
+}
+
+// Contract collapses every run of nodes with exactly two neighbors into a single edge
+// whose cost is the sum of the run's costs. Nodes in keep always stay, like the start
+// and end of a maze. Edge directions are respected: a corridor that can only be walked
+// one way becomes a one-way edge, and one that can't be walked through at all is dropped.
+func (graph *Graph[T]) Contract(keep ...T) Contraction[T] {
+	undirected := graph.Undirected()
+	isJunction := func(node T) bool {
+		return undirected.Degree(node) != 2 || slices.Contains(keep, node)
+	}
+
+	ret := Contraction[T]{Graph: make(Graph[T]), Corridors: make(map[Edge[T]][][]T)}
+	for _, node := range graph.Nodes() {
+		if !isJunction(node) {
+			continue
+		}
+		ret.Graph[node] = make([]Neighbor[T], 0)
+		for _, first := range (*graph)[node] {
+			corridor := []T{node, first.Value}
+			cost := first.Cost
+			prev, curr := node, first.Value
+			deadEnd := false
+			for !isJunction(curr) && !deadEnd {
+				deadEnd = true
+				for _, next := range (*graph)[curr] {
+					if next.Value != prev {
+						prev, curr = curr, next.Value
+						corridor = append(corridor, curr)
+						cost += next.Cost
+						deadEnd = false
+						break
+					}
+				}
+			}
+			if deadEnd || curr == node {
+				continue
+			}
+			edge := Edge[T]{From: node, To: curr, Cost: cost}
+			if _, seen := ret.Corridors[edge]; !seen {
+				ret.Graph.AddEdge(node, curr, cost)
+			}
+			ret.Corridors[edge] = append(ret.Corridors[edge], corridor)
+		}
+	}
+	return ret
+}
+
+// Expand turns a list of contracted edges back into the original nodes along them. An
+// edge standing for several corridors takes the first one.
+func (c *Contraction[T]) Expand(edges []Edge[T]) []T {
+	ret := make([]T, 0)
+	for i, edge := range edges {
+		corridor := c.Corridors[edge][0]
+		if i > 0 {
+			// the first node is the end of the previous corridor.
+			corridor = corridor[1:]
+		}
+		ret = append(ret, corridor...)
+	}
+	return ret
+}
+
+type bitset []uint64
+
+func newBitset(size int) bitset {
+	return make(bitset, (size+63)/64)
+}
+
+func (b bitset) get(i int) bool {
+	return b[i/64]&(1<<(i%64)) != 0
+}
+
+func (b bitset) set(i int, value bool) {
+	if value {
+		b[i/64] |= 1 << (i % 64)
+	} else {
+		b[i/64] &^= 1 << (i % 64)
+	}
+}
+
+// LongestSimplePath finds the most costly path from from to to that doesn't visit any
+// node twice, by trying them all. That's exponential, so Contract mazes first. It
+// returns the edges taken, which Contraction.Expand can turn back into nodes.
+func (graph *Graph[T]) LongestSimplePath(from T, to T) (int, []Edge[T], error) {
+	nodes := graph.Nodes()
+	index := make(map[T]int, len(nodes))
+	for i, node := range nodes {
+		index[node] = i
+	}
+	if _, ok := index[from]; !ok {
+		return -1, nil, ErrNoPath
+	}
+	type indexedEdge struct {
+		to   int
+		cost int
+		edge Edge[T]
+	}
+	adjacent := make([][]indexedEdge, len(nodes))
+	for _, edge := range graph.Edges() {
+		adjacent[index[edge.From]] = append(adjacent[index[edge.From]], indexedEdge{to: index[edge.To], cost: edge.Cost, edge: edge})
+	}
+
+	target, hasTarget := index[to]
+	if !hasTarget {
+		return -1, nil, ErrNoPath
+	}
+	visited := newBitset(len(nodes))
+	path := make([]Edge[T], 0)
+	bestCost := -1
+	var bestPath []Edge[T]
+	var walk func(curr int, cost int)
+	walk = func(curr int, cost int) {
+		if curr == target {
+			if cost > bestCost {
+				bestCost = cost
+				bestPath = slices.Clone(path)
+			}
+			return
+		}
+		visited.set(curr, true)
+		for _, next := range adjacent[curr] {
+			if visited.get(next.to) {
+				continue
+			}
+			path = append(path, next.edge)
+			walk(next.to, cost+next.cost)
+			path = path[:len(path)-1]
+		}
+		visited.set(curr, false)
+	}
+	walk(index[from], 0)
+	if bestCost < 0 {
+		return -1, nil, ErrNoPath
+	}
+	return bestCost, bestPath, nil
+}
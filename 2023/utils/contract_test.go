@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var testMaze = Grid[rune]{
+	[]rune("#.#########"),
+	[]rune("#...#.....#"),
+	[]rune("###.#.###.#"),
+	[]rune("#...>...#.#"),
+	[]rune("#.#####.#.#"),
+	[]rune("#.......v.#"),
+	[]rune("#######.###"),
+	[]rune("#######.###"),
+	[]rune("#######...#"),
+	[]rune("#########.#"),
+}
+
+// makeMazeGraph joins open cells, but only lets slopes be walked downhill.
+func makeMazeGraph(maze Grid[rune]) Graph[Position] {
+	graph := make(Graph[Position])
+	for pos, r := range maze.All() {
+		if r == '#' {
+			continue
+		}
+		for d, next := range maze.Neighbors4(pos) {
+			if maze.ItemAt(next) == '#' {
+				continue
+			}
+			if slope, err := ParseDirection(r, Arrows); err == nil && slope != d {
+				continue
+			}
+			graph.AddEdge(pos, next, 1)
+		}
+	}
+	return graph
+}
+
+func TestContract(t *testing.T) {
+	graph := makeMazeGraph(testMaze)
+	start, end := Position{X: 1, Y: 0}, Position{X: 9, Y: 9}
+	contracted := graph.Contract(start, end)
+	if len(contracted.Graph) >= len(graph) {
+		t.Errorf("Contract() kept %d of %d nodes", len(contracted.Graph), len(graph))
+	}
+	for edge, corridors := range contracted.Corridors {
+		for _, corridor := range corridors {
+			if corridor[0] != edge.From || corridor[len(corridor)-1] != edge.To || len(corridor)-1 != edge.Cost {
+				t.Errorf("corridor for %v is %v", edge, corridor)
+			}
+		}
+	}
+
+	slowCost, _, err := graph.LongestSimplePath(start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cost, edges, err := contracted.Graph.LongestSimplePath(start, end)
+	if err != nil || cost != slowCost {
+		t.Fatalf("LongestSimplePath() on the contracted graph = %v, %v, want %v", cost, err, slowCost)
+	}
+
+	path := contracted.Expand(edges)
+	if len(path) != cost+1 || path[0] != start || path[len(path)-1] != end {
+		t.Errorf("Expand() = %v, want %d steps from %v to %v", path, cost, start, end)
+	}
+	seen := make(map[Position]bool)
+	for i, pos := range path {
+		if seen[pos] {
+			t.Errorf("Expand() visits %v twice", pos)
+		}
+		seen[pos] = true
+		if i > 0 && pos.ManhattanDistance(path[i-1]) != 1 {
+			t.Errorf("Expand() jumps from %v to %v", path[i-1], pos)
+		}
+	}
+
+	// ignoring the slopes opens up a longer route.
+	undirected := graph.Undirected()
+	bothWays := undirected.Directed(1)
+	contractedBothWays := bothWays.Contract(start, end)
+	undirectedCost, _, _ := contractedBothWays.Graph.LongestSimplePath(start, end)
+	if undirectedCost <= cost {
+		t.Errorf("LongestSimplePath() without slopes = %v, want more than %v", undirectedCost, cost)
+	}
+}
+
+func TestContractParallelCorridors(t *testing.T) {
+	// a reaches b through x or through y, which cost the same.
+	graph := makeStringGraph("ax", "xb", "ay", "yb")
+	contracted := graph.Contract("a", "b")
+	corridors := contracted.Corridors[Edge[string]{From: "a", To: "b", Cost: 2}]
+	got := make([]string, len(corridors))
+	for i, corridor := range corridors {
+		got[i] = strings.Join(corridor, "")
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, []string{"axb", "ayb"}) {
+		t.Errorf("Corridors[a->b] = %v, want axb and ayb", got)
+	}
+	if len(contracted.Graph["a"]) != 1 {
+		t.Errorf("Contract() gave a %d edges, want 1", len(contracted.Graph["a"]))
+	}
+}
+
+func TestLongestSimplePathNoPath(t *testing.T) {
+	graph := makeStringGraph("ab", "cb")
+	if _, _, err := graph.LongestSimplePath("a", "c"); !errors.Is(err, ErrNoPath) {
+		t.Errorf("LongestSimplePath() error = %v, want ErrNoPath", err)
+	}
+}
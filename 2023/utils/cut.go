@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrTooFewNodes = errors.New("graph needs at least two nodes to cut")
+
+// MaxFlow treats each edge's Cost as its capacity and returns the most that can flow
+// from source to sink, using Edmonds-Karp. It also returns the edges of a minimum cut:
+// the saturated edges from the side of the graph source can still reach to the rest.
+// Their costs add up to the flow. For an undirected graph, add each edge both ways.
+func (graph *Graph[T]) MaxFlow(source T, sink T) (int, []Edge[T]) {
+	if source == sink {
+		return 0, nil
+	}
+	// residual[u][v] is how much more can be pushed from u to v. pushing flow u->v
+	// adds the same amount to residual[v][u], so it can be taken back later.
+	residual := make(map[T]map[T]int)
+	addResidual := func(from, to T, amount int) {
+		if residual[from] == nil {
+			residual[from] = make(map[T]int)
+		}
+		residual[from][to] += amount
+	}
+	for _, edge := range graph.Edges() {
+		addResidual(edge.From, edge.To, edge.Cost)
+		addResidual(edge.To, edge.From, 0)
+	}
+
+	reachableInResidual := func() (map[T]T, bool) {
+		parent := map[T]T{source: source}
+		toVisit := []T{source}
+		for len(toVisit) > 0 {
+			curr := toVisit[0]
+			toVisit = toVisit[1:]
+			for next, capacity := range residual[curr] {
+				if _, seen := parent[next]; seen || capacity <= 0 {
+					continue
+				}
+				parent[next] = curr
+				if next == sink {
+					return parent, true
+				}
+				toVisit = append(toVisit, next)
+			}
+		}
+		return parent, false
+	}
+
+	flow := 0
+	for {
+		parent, found := reachableInResidual()
+		if !found {
+			ret := make([]Edge[T], 0)
+			for _, edge := range graph.Edges() {
+				_, fromReachable := parent[edge.From]
+				_, toReachable := parent[edge.To]
+				if fromReachable && !toReachable {
+					ret = append(ret, edge)
+				}
+			}
+			return flow, ret
+		}
+		bottleneck := math.MaxInt
+		for v := sink; v != source; v = parent[v] {
+			bottleneck = min(bottleneck, residual[parent[v]][v])
+		}
+		for v := sink; v != source; v = parent[v] {
+			residual[parent[v]][v] -= bottleneck
+			residual[v][parent[v]] += bottleneck
+		}
+		flow += bottleneck
+	}
+}
+
+// GlobalMinCut finds the cheapest set of edges whose removal splits the graph in two,
+// using Stoer-Wagner. Edges are undirected: an edge stored both ways, as
+// AddUndirectedEdge does, counts once. It returns the weight of the cut and the nodes
+// on one side of it.
+func (graph *Graph[T]) GlobalMinCut() (int, []T, error) {
+	nodes := graph.Nodes()
+	if len(nodes) < 2 {
+		return 0, nil, ErrTooFewNodes
+	}
+	index := make(map[T]int, len(nodes))
+	for i, node := range nodes {
+		index[node] = i
+	}
+	directed := make([]map[int]int, len(nodes))
+	for i := range directed {
+		directed[i] = make(map[int]int)
+	}
+	for _, edge := range graph.Edges() {
+		if edge.From != edge.To {
+			directed[index[edge.From]][index[edge.To]] += edge.Cost
+		}
+	}
+	weights := make([]map[int]int, len(nodes))
+	members := make([][]int, len(nodes))
+	active := make(map[int]bool, len(nodes))
+	for u := range nodes {
+		weights[u] = make(map[int]int)
+		members[u] = []int{u}
+		active[u] = true
+	}
+	// fill in both directions from whichever way the edge was stored, so one-way edges
+	// are still symmetric here.
+	for u := range nodes {
+		for v, w := range directed[u] {
+			w = max(w, directed[v][u])
+			weights[u][v] = w
+			weights[v][u] = w
+		}
+	}
+
+	bestWeight := -1
+	var bestSide []int
+	for len(active) > 1 {
+		// add nodes one at a time, always picking the one most tightly connected to
+		// those already added. priorities are negated since the heap pops the minimum.
+		toAdd := NewIndexedHeap[int]()
+		for u := range active {
+			toAdd.Push(u, 0)
+		}
+		prev, last, cutOfPhase := -1, -1, 0
+		for toAdd.Len() > 0 {
+			u, priority, _ := toAdd.PopMin()
+			prev, last, cutOfPhase = last, u, -priority
+			for v, w := range weights[u] {
+				if p, ok := toAdd.Priority(v); ok {
+					toAdd.DecreaseKey(v, p-w)
+				}
+			}
+		}
+		// the weight connecting last to everything else is a cut of the original graph.
+		if bestWeight < 0 || cutOfPhase < bestWeight {
+			bestWeight = cutOfPhase
+			bestSide = append([]int(nil), members[last]...)
+		}
+		// then merge last into prev and go again.
+		members[prev] = append(members[prev], members[last]...)
+		for v, w := range weights[last] {
+			delete(weights[v], last)
+			if v != prev {
+				weights[prev][v] += w
+				weights[v][prev] += w
+			}
+		}
+		weights[last] = nil
+		delete(active, last)
+	}
+
+	side := make([]T, len(bestSide))
+	for i, u := range bestSide {
+		side[i] = nodes[u]
+	}
+	return bestWeight, side, nil
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestUnionFind(t *testing.T) {
+	uf := NewUnionFind[int]()
+	uf.Union(1, 2)
+	uf.Union(3, 4)
+	if uf.Connected(1, 3) {
+		t.Error("Connected(1, 3) = true before joining them")
+	}
+	if !uf.Union(2, 4) || uf.Union(1, 3) {
+		t.Error("Union() didn't report merges correctly")
+	}
+	if !uf.Connected(1, 4) || uf.Size(3) != 4 || uf.Size(5) != 1 {
+		t.Errorf("after merging, Connected(1, 4) = %v, Size(3) = %v, Size(5) = %v", uf.Connected(1, 4), uf.Size(3), uf.Size(5))
+	}
+	if got := len(uf.Groups()); got != 2 {
+		t.Errorf("len(Groups()) = %v, want 2", got)
+	}
+}
+
+func TestConnectedComponents(t *testing.T) {
+	graph := makeStringGraph("ab", "cb", "de", "ff")
+	var got []string
+	for _, component := range graph.ConnectedComponents() {
+		slices.Sort(component)
+		got = append(got, strings.Join(component, ""))
+	}
+	slices.Sort(got)
+	if want := []string{"abc", "de", "f"}; !slices.Equal(got, want) {
+		t.Errorf("ConnectedComponents() = %v, want %v", got, want)
+	}
+}
+
+func TestMaxFlow(t *testing.T) {
+	// the classic CLRS example, with s=s, t=t.
+	graph := make(Graph[string])
+	graph.AddEdge("s", "a", 16)
+	graph.AddEdge("s", "c", 13)
+	graph.AddEdge("a", "b", 12)
+	graph.AddEdge("c", "a", 4)
+	graph.AddEdge("b", "c", 9)
+	graph.AddEdge("c", "d", 14)
+	graph.AddEdge("d", "b", 7)
+	graph.AddEdge("b", "t", 20)
+	graph.AddEdge("d", "t", 4)
+
+	flow, cut := graph.MaxFlow("s", "t")
+	if flow != 23 {
+		t.Errorf("MaxFlow() = %v, want 23", flow)
+	}
+	cutWeight := 0
+	for _, edge := range cut {
+		cutWeight += edge.Cost
+	}
+	if cutWeight != flow {
+		t.Errorf("MaxFlow() cut %v weighs %d, want %d", cut, cutWeight, flow)
+	}
+}
+
+func TestGlobalMinCut(t *testing.T) {
+	// two groups of five, each fully connected, joined by three wires like "cut three
+	// wires to split the network".
+	graph := make(Graph[string])
+	for _, half := range []string{"abcde", "fghij"} {
+		for i := range half {
+			for j := i + 1; j < len(half); j++ {
+				graph.AddUndirectedEdge(half[i:i+1], half[j:j+1], 1)
+			}
+		}
+	}
+	for _, edge := range []string{"af", "bg", "ch"} {
+		graph.AddUndirectedEdge(edge[0:1], edge[1:2], 1)
+	}
+	weight, side, err := graph.GlobalMinCut()
+	if err != nil || weight != 3 {
+		t.Fatalf("GlobalMinCut() = %v, %v, want 3", weight, err)
+	}
+	slices.Sort(side)
+	if got := strings.Join(side, ""); got != "abcde" && got != "fghij" {
+		t.Errorf("GlobalMinCut() side = %v, want abcde or fghij", side)
+	}
+
+	// the same cut found by max flow between the two halves.
+	flow, cut := graph.MaxFlow("d", "j")
+	if flow != 3 || len(cut) != 3 {
+		t.Errorf("MaxFlow() = %v, %v, want 3 edges", flow, cut)
+	}
+
+	// edges added one way only count the same as undirected ones.
+	oneWay := make(Graph[string])
+	for _, edge := range []string{"ab", "bc", "ca", "cd"} {
+		oneWay.AddEdge(edge[0:1], edge[1:2], 1)
+	}
+	weight, side, err = oneWay.GlobalMinCut()
+	if err != nil || weight != 1 {
+		t.Fatalf("GlobalMinCut() with one-way edges = %v, %v, want 1", weight, err)
+	}
+	slices.Sort(side)
+	if got := strings.Join(side, ""); got != "d" && got != "abc" {
+		t.Errorf("GlobalMinCut() side = %v, want d or abc", side)
+	}
+
+	single := makeStringGraph("aa")
+	if _, _, err := single.GlobalMinCut(); !errors.Is(err, ErrTooFewNodes) {
+		t.Errorf("GlobalMinCut() on one node error = %v, want ErrTooFewNodes", err)
+	}
+}
@@ -0,0 +1,298 @@
+package utils
+
+import (
+	"errors"
+	"iter"
+	"slices"
+)
+
+var ErrNoCycle = errors.New("no cycle found")
+
+// Nodes returns every node in the graph, including ones that only appear as the
+// target of an edge.
+func (graph *Graph[T]) Nodes() []T {
+	seen := make(map[T]bool)
+	ret := make([]T, 0, len(*graph))
+	add := func(node T) {
+		if !seen[node] {
+			seen[node] = true
+			ret = append(ret, node)
+		}
+	}
+	for from, tos := range *graph {
+		add(from)
+		for _, to := range tos {
+			add(to.Value)
+		}
+	}
+	return ret
+}
+
+func pathFromParents[T comparable](parent map[T]T, root T, to T) []T {
+	path := []T{to}
+	for to != root {
+		to = parent[to]
+		path = append(path, to)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// FindCycleThrough returns the shortest directed cycle through node, starting with node.
+// The edge from the last element back to node is implied.
+func (graph *Graph[T]) FindCycleThrough(node T) ([]T, error) {
+	parent := make(map[T]T)
+	seen := map[T]bool{node: true}
+	toVisit := []T{node}
+	for len(toVisit) > 0 {
+		curr := toVisit[0]
+		toVisit = toVisit[1:]
+		for _, neighbor := range (*graph)[curr] {
+			if neighbor.Value == node {
+				return pathFromParents(parent, node, curr), nil
+			}
+			if seen[neighbor.Value] {
+				continue
+			}
+			seen[neighbor.Value] = true
+			parent[neighbor.Value] = curr
+			toVisit = append(toVisit, neighbor.Value)
+		}
+	}
+	return nil, ErrNoCycle
+}
+
+// FindUndirectedCycleThrough is FindCycleThrough for undirected graphs: edges can be
+// followed either way, but never straight back along the edge just taken, so a-b-a
+// doesn't count. This is what a loop of pipes needs.
+func (graph *Graph[T]) FindUndirectedCycleThrough(node T) ([]T, error) {
+	adjacent := make(map[T][]T)
+	for from, tos := range *graph {
+		for _, to := range tos {
+			if from == to.Value {
+				continue
+			}
+			if !slices.Contains(adjacent[from], to.Value) {
+				adjacent[from] = append(adjacent[from], to.Value)
+			}
+			if !slices.Contains(adjacent[to.Value], from) {
+				adjacent[to.Value] = append(adjacent[to.Value], from)
+			}
+		}
+	}
+
+	// breadth-first from node, remembering which of node's neighbors each node was
+	// reached through. any edge joining two different branches closes a cycle.
+	parent := make(map[T]T)
+	branch := make(map[T]T)
+	distances := map[T]int{node: 0}
+	toVisit := []T{node}
+	bestLength := -1
+	var bestEdge [2]T
+	for len(toVisit) > 0 {
+		curr := toVisit[0]
+		toVisit = toVisit[1:]
+		for _, neighbor := range adjacent[curr] {
+			if neighbor == node {
+				if curr != node && parent[curr] != node {
+					if length := distances[curr] + 1; bestLength < 0 || length < bestLength {
+						bestLength, bestEdge = length, [2]T{curr, node}
+					}
+				}
+				continue
+			}
+			if _, seen := distances[neighbor]; !seen {
+				distances[neighbor] = distances[curr] + 1
+				parent[neighbor] = curr
+				if curr == node {
+					branch[neighbor] = neighbor
+				} else {
+					branch[neighbor] = branch[curr]
+				}
+				toVisit = append(toVisit, neighbor)
+			} else if curr != node && branch[neighbor] != branch[curr] {
+				if length := distances[curr] + distances[neighbor] + 1; bestLength < 0 || length < bestLength {
+					bestLength, bestEdge = length, [2]T{curr, neighbor}
+				}
+			}
+		}
+	}
+	if bestLength < 0 {
+		return nil, ErrNoCycle
+	}
+	ret := pathFromParents(parent, node, bestEdge[0])
+	if bestEdge[1] != node {
+		back := pathFromParents(parent, node, bestEdge[1])[1:]
+		slices.Reverse(back)
+		ret = append(ret, back...)
+	}
+	return ret, nil
+}
+
+// FindAnyCycle returns some directed cycle in the graph, in the same form as
+// FindCycleThrough, or ErrNoCycle if the graph is acyclic.
+func (graph *Graph[T]) FindAnyCycle() ([]T, error) {
+	const (
+		unvisited = iota
+		onStack
+		done
+	)
+	state := make(map[T]int)
+	stack := make([]T, 0)
+	var cycle []T
+	var visit func(node T) bool
+	visit = func(node T) bool {
+		state[node] = onStack
+		stack = append(stack, node)
+		for _, neighbor := range (*graph)[node] {
+			switch state[neighbor.Value] {
+			case onStack:
+				start := slices.Index(stack, neighbor.Value)
+				cycle = slices.Clone(stack[start:])
+				return true
+			case unvisited:
+				if visit(neighbor.Value) {
+					return true
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[node] = done
+		return false
+	}
+	for _, node := range graph.Nodes() {
+		if state[node] == unvisited && visit(node) {
+			return cycle, nil
+		}
+	}
+	return nil, ErrNoCycle
+}
+
+// SimpleCycles yields every elementary directed cycle exactly once, using Johnson's
+// algorithm (https://www.cs.tufts.edu/comp/150GA/homeworks/hw1/Johnson%2075.PDF).
+// Each cycle is in the same form as FindCycleThrough. There can be exponentially
+// many, so they're only found as they're asked for. The slice is reused between
+// cycles; clone it to keep it.
+func (graph *Graph[T]) SimpleCycles() iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		nodes := graph.Nodes()
+		index := make(map[T]int, len(nodes))
+		for i, node := range nodes {
+			index[node] = i
+		}
+		adjacent := make([][]int, len(nodes))
+		for from, tos := range *graph {
+			for _, to := range tos {
+				if !slices.Contains(adjacent[index[from]], index[to.Value]) {
+					adjacent[index[from]] = append(adjacent[index[from]], index[to.Value])
+				}
+			}
+		}
+
+		blocked := make([]bool, len(nodes))
+		blockedBy := make([]map[int]bool, len(nodes))
+		var unblock func(u int)
+		unblock = func(u int) {
+			blocked[u] = false
+			for w := range blockedBy[u] {
+				delete(blockedBy[u], w)
+				if blocked[w] {
+					unblock(w)
+				}
+			}
+		}
+
+		stack := make([]int, 0)
+		out := make([]T, 0)
+		stopped := false
+		// cycles are found starting from each node s in turn, only ever using nodes
+		// after s, so each cycle is found from its lowest-numbered node.
+		for s := range nodes {
+			component := strongComponentOf(adjacent, s)
+			if len(component) == 1 && !slices.Contains(adjacent[s], s) {
+				continue
+			}
+			for v := range component {
+				blocked[v] = false
+				blockedBy[v] = make(map[int]bool)
+			}
+			var circuit func(v int) bool
+			circuit = func(v int) bool {
+				foundCycle := false
+				stack = append(stack, v)
+				blocked[v] = true
+				for _, w := range adjacent[v] {
+					if stopped {
+						break
+					}
+					if !component[w] {
+						continue
+					}
+					if w == s {
+						out = out[:0]
+						for _, i := range stack {
+							out = append(out, nodes[i])
+						}
+						if !yield(out) {
+							stopped = true
+						}
+						foundCycle = true
+					} else if !blocked[w] && circuit(w) {
+						foundCycle = true
+					}
+				}
+				if foundCycle {
+					unblock(v)
+				} else {
+					for _, w := range adjacent[v] {
+						if component[w] {
+							blockedBy[w][v] = true
+						}
+					}
+				}
+				stack = stack[:len(stack)-1]
+				return foundCycle
+			}
+			circuit(s)
+			if stopped {
+				return
+			}
+		}
+	}
+}
+
+// strongComponentOf returns the nodes in the same strongly connected component as s,
+// looking only at nodes numbered s or higher: those reachable from s that can also
+// reach it.
+func strongComponentOf(adjacent [][]int, s int) map[int]bool {
+	forward := map[int]bool{s: true}
+	toVisit := []int{s}
+	reverse := make(map[int][]int)
+	for len(toVisit) > 0 {
+		curr := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+		for _, next := range adjacent[curr] {
+			if next < s {
+				continue
+			}
+			reverse[next] = append(reverse[next], curr)
+			if !forward[next] {
+				forward[next] = true
+				toVisit = append(toVisit, next)
+			}
+		}
+	}
+	ret := map[int]bool{s: true}
+	toVisit = append(toVisit, s)
+	for len(toVisit) > 0 {
+		curr := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+		for _, prev := range reverse[curr] {
+			if !ret[prev] {
+				ret[prev] = true
+				toVisit = append(toVisit, prev)
+			}
+		}
+	}
+	return ret
+}
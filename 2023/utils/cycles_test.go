@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// canonicalCycle rotates a cycle to start at its smallest node so cycles found from
+// different starting points compare equal.
+func canonicalCycle(cycle []string) string {
+	start := slices.Index(cycle, slices.Min(cycle))
+	return strings.Join(slices.Concat(cycle[start:], cycle[:start]), "")
+}
+
+func makeStringGraph(edges ...string) Graph[string] {
+	graph := make(Graph[string])
+	for _, edge := range edges {
+		graph.AddEdge(edge[0:1], edge[1:2], 1)
+	}
+	return graph
+}
+
+func TestFindCycleThrough(t *testing.T) {
+	// a branches to b and c, which both lead back to a; the c route is shorter.
+	graph := makeStringGraph("ab", "bd", "de", "ea", "ac", "ca", "cf")
+	cycle, err := graph.FindCycleThrough("a")
+	if err != nil || strings.Join(cycle, "") != "ac" {
+		t.Errorf("FindCycleThrough(a) = %v, %v, want [a c]", cycle, err)
+	}
+	cycle, err = graph.FindCycleThrough("d")
+	if err != nil || strings.Join(cycle, "") != "deab" {
+		t.Errorf("FindCycleThrough(d) = %v, %v, want [d e a b]", cycle, err)
+	}
+	if _, err := graph.FindCycleThrough("f"); !errors.Is(err, ErrNoCycle) {
+		t.Errorf("FindCycleThrough(f) error = %v, want ErrNoCycle", err)
+	}
+}
+
+func TestFindUndirectedCycleThrough(t *testing.T) {
+	// a triangle a-b-c with a tail c-d-e, and a square c-e-f-g hanging off it.
+	graph := makeStringGraph("ab", "bc", "ca", "cd", "de", "ef", "fg", "gc")
+	tests := []struct {
+		node string
+		want int
+	}{
+		{"a", 3},
+		{"c", 3},
+		{"d", 5},
+		{"f", 5},
+	}
+	for _, tt := range tests {
+		cycle, err := graph.FindUndirectedCycleThrough(tt.node)
+		if err != nil || len(cycle) != tt.want || cycle[0] != tt.node {
+			t.Errorf("FindUndirectedCycleThrough(%v) = %v, %v, want a cycle of length %d", tt.node, cycle, err, tt.want)
+			continue
+		}
+		// consecutive nodes, including last and first, must be joined by an edge.
+		for i := range cycle {
+			from, to := cycle[i], cycle[(i+1)%len(cycle)]
+			if !hasEdge(graph, from, to) && !hasEdge(graph, to, from) {
+				t.Errorf("FindUndirectedCycleThrough(%v) = %v, which has no edge %v-%v", tt.node, cycle, from, to)
+			}
+		}
+	}
+
+	// a path with edges both ways, like pipes, has no cycle.
+	path := makeStringGraph("ab", "ba", "bc", "cb")
+	if _, err := path.FindUndirectedCycleThrough("b"); !errors.Is(err, ErrNoCycle) {
+		t.Errorf("FindUndirectedCycleThrough on a path error = %v, want ErrNoCycle", err)
+	}
+}
+
+func hasEdge(graph Graph[string], from, to string) bool {
+	return slices.ContainsFunc(graph[from], func(n Neighbor[string]) bool { return n.Value == to })
+}
+
+func TestFindAnyCycle(t *testing.T) {
+	dag := makeStringGraph("ab", "ac", "bd", "cd", "de")
+	if cycle, err := dag.FindAnyCycle(); !errors.Is(err, ErrNoCycle) {
+		t.Errorf("FindAnyCycle() on a DAG = %v, %v, want ErrNoCycle", cycle, err)
+	}
+
+	graph := makeStringGraph("ab", "ac", "bd", "cd", "de", "eb")
+	cycle, err := graph.FindAnyCycle()
+	if err != nil || canonicalCycle(cycle) != "bde" {
+		t.Errorf("FindAnyCycle() = %v, %v, want [b d e]", cycle, err)
+	}
+
+	selfLoop := makeStringGraph("ab", "bb")
+	if cycle, err := selfLoop.FindAnyCycle(); err != nil || canonicalCycle(cycle) != "b" {
+		t.Errorf("FindAnyCycle() with a self loop = %v, %v, want [b]", cycle, err)
+	}
+}
+
+func TestSimpleCycles(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph Graph[string]
+		want  []string
+	}{
+		{"dag", makeStringGraph("ab", "ac", "bd", "cd"), nil},
+		{"self loop", makeStringGraph("aa", "ab"), []string{"a"}},
+		{"two way", makeStringGraph("ab", "ba"), []string{"ab"}},
+		// two cycles sharing the a->b edge, plus one through all four nodes.
+		{"branching", makeStringGraph("ab", "bc", "ca", "bd", "da", "cd"), []string{"abc", "abcd", "abd"}},
+		// every ordered pair is an edge, so every subset of 2 or more nodes has (k-1)! cycles.
+		{"complete", makeStringGraph("ab", "ba", "ac", "ca", "bc", "cb"), []string{"ab", "abc", "ac", "acb", "bc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for cycle := range tt.graph.SimpleCycles() {
+				got = append(got, canonicalCycle(cycle))
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SimpleCycles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// stopping early shouldn't panic or keep going.
+	complete := makeStringGraph("ab", "ba", "ac", "ca", "bc", "cb")
+	count := 0
+	for range complete.SimpleCycles() {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("SimpleCycles() yielded %d cycles after break", count)
+	}
+}
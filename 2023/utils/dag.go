@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrCycle = errors.New("graph has a cycle")
+
+// StronglyConnectedComponents groups the nodes so that every node in a component can
+// reach every other one, using Tarjan's algorithm. Components come out in reverse
+// topological order: nothing in a component has an edge to a later component.
+func (graph *Graph[T]) StronglyConnectedComponents() [][]T {
+	index := make(map[T]int)
+	lowLink := make(map[T]int)
+	onStack := make(map[T]bool)
+	stack := make([]T, 0)
+	ret := make([][]T, 0)
+	nextIndex := 0
+
+	var strongConnect func(node T)
+	strongConnect = func(node T) {
+		index[node] = nextIndex
+		lowLink[node] = nextIndex
+		nextIndex++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, neighbor := range (*graph)[node] {
+			if _, visited := index[neighbor.Value]; !visited {
+				strongConnect(neighbor.Value)
+				lowLink[node] = min(lowLink[node], lowLink[neighbor.Value])
+			} else if onStack[neighbor.Value] {
+				lowLink[node] = min(lowLink[node], index[neighbor.Value])
+			}
+		}
+
+		// node is the root of a component, which is everything above it on the stack.
+		if lowLink[node] == index[node] {
+			component := make([]T, 0)
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == node {
+					break
+				}
+			}
+			ret = append(ret, component)
+		}
+	}
+
+	for _, node := range graph.Nodes() {
+		if _, visited := index[node]; !visited {
+			strongConnect(node)
+		}
+	}
+	return ret
+}
+
+// TopologicalSort orders the nodes so that every edge goes from an earlier node to a
+// later one. If there's a cycle, the error wraps ErrCycle and names one of them.
+func (graph *Graph[T]) TopologicalSort() ([]T, error) {
+	nodes := graph.Nodes()
+	inDegree := make(map[T]int)
+	for _, tos := range *graph {
+		for _, to := range tos {
+			inDegree[to.Value]++
+		}
+	}
+	toVisit := make([]T, 0)
+	for _, node := range nodes {
+		if inDegree[node] == 0 {
+			toVisit = append(toVisit, node)
+		}
+	}
+	ret := make([]T, 0, len(nodes))
+	for len(toVisit) > 0 {
+		curr := toVisit[0]
+		toVisit = toVisit[1:]
+		ret = append(ret, curr)
+		for _, neighbor := range (*graph)[curr] {
+			inDegree[neighbor.Value]--
+			if inDegree[neighbor.Value] == 0 {
+				toVisit = append(toVisit, neighbor.Value)
+			}
+		}
+	}
+	if len(ret) < len(nodes) {
+		cycle, _ := graph.FindAnyCycle()
+		return nil, fmt.Errorf("%w: %v", ErrCycle, cycle)
+	}
+	return ret, nil
+}
+
+// ReachableFrom returns every node that can be reached from any of sources, including
+// the sources themselves.
+func (graph *Graph[T]) ReachableFrom(sources ...T) map[T]bool {
+	ret := make(map[T]bool)
+	toVisit := make([]T, 0)
+	for _, source := range sources {
+		if !ret[source] {
+			ret[source] = true
+			toVisit = append(toVisit, source)
+		}
+	}
+	for len(toVisit) > 0 {
+		curr := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+		for _, neighbor := range (*graph)[curr] {
+			if !ret[neighbor.Value] {
+				ret[neighbor.Value] = true
+				toVisit = append(toVisit, neighbor.Value)
+			}
+		}
+	}
+	return ret
+}
+
+// LongestPathsFrom is DistancesFrom, but for the longest path to each node instead of the
+// shortest. That's only well defined without cycles, so if one can be reached from
+// sources the error wraps ErrCycle.
+func (graph *Graph[T]) LongestPathsFrom(sources []T) (map[T]int, map[T]T, error) {
+	reachable := graph.ReachableFrom(sources...)
+	subgraph := make(Graph[T])
+	for node := range reachable {
+		if tos, ok := (*graph)[node]; ok {
+			subgraph[node] = tos
+		}
+	}
+	order, err := subgraph.TopologicalSort()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	distances := make(map[T]int)
+	prev := make(map[T]T)
+	for _, source := range sources {
+		distances[source] = 0
+	}
+	// everything in order is reachable, and whatever it's reached through comes
+	// earlier, so its distance is final by the time it's visited.
+	for _, curr := range order {
+		currDistance := distances[curr]
+		for _, neighbor := range (*graph)[curr] {
+			if prevDistance, ok := distances[neighbor.Value]; !ok || currDistance+neighbor.Cost > prevDistance {
+				distances[neighbor.Value] = currDistance + neighbor.Cost
+				prev[neighbor.Value] = curr
+			}
+		}
+	}
+	return distances, prev, nil
+}
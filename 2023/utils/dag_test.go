@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	// {a b c} is a cycle that leads into the cycle {d e}, which leads to f.
+	graph := makeStringGraph("ab", "bc", "ca", "cd", "de", "ed", "ef")
+	components := graph.StronglyConnectedComponents()
+	got := make([]string, 0)
+	position := make(map[string]int)
+	for i, component := range components {
+		slices.Sort(component)
+		for _, node := range component {
+			position[node] = i
+		}
+		got = append(got, canonicalCycle(component))
+	}
+	slices.Sort(got)
+	if want := []string{"abc", "de", "f"}; !slices.Equal(got, want) {
+		t.Errorf("StronglyConnectedComponents() = %v, want %v", got, want)
+	}
+	// reverse topological order.
+	if !(position["f"] < position["d"] && position["d"] < position["a"]) {
+		t.Errorf("StronglyConnectedComponents() order = %v, want f's before d's before a's", components)
+	}
+}
+
+func TestTopologicalSort(t *testing.T) {
+	graph := makeStringGraph("ab", "ac", "bd", "cd", "de", "xe")
+	order, err := graph.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort() error = %v", err)
+	}
+	if len(order) != 6 {
+		t.Errorf("TopologicalSort() = %v, want all 6 nodes", order)
+	}
+	for from, tos := range graph {
+		for _, to := range tos {
+			if slices.Index(order, from) > slices.Index(order, to.Value) {
+				t.Errorf("TopologicalSort() = %v, but %v -> %v", order, from, to.Value)
+			}
+		}
+	}
+
+	graph.AddEdge("e", "b", 1)
+	if _, err := graph.TopologicalSort(); !errors.Is(err, ErrCycle) {
+		t.Errorf("TopologicalSort() with a cycle error = %v, want ErrCycle", err)
+	}
+}
+
+func TestReachableFrom(t *testing.T) {
+	graph := makeStringGraph("ab", "bc", "cb", "de")
+	got := slices.Sorted(maps.Keys(graph.ReachableFrom("a")))
+	if want := []string{"a", "b", "c"}; !slices.Equal(got, want) {
+		t.Errorf("ReachableFrom(a) = %v, want %v", got, want)
+	}
+	got = slices.Sorted(maps.Keys(graph.ReachableFrom("c", "d")))
+	if want := []string{"b", "c", "d", "e"}; !slices.Equal(got, want) {
+		t.Errorf("ReachableFrom(c, d) = %v, want %v", got, want)
+	}
+}
+
+func TestLongestPathsFrom(t *testing.T) {
+	graph := make(Graph[string])
+	graph.AddEdge("a", "b", 1)
+	graph.AddEdge("a", "c", 4)
+	graph.AddEdge("b", "c", 2)
+	graph.AddEdge("c", "d", 1)
+	graph.AddEdge("b", "d", 7)
+	// a cycle that can't be reached from a doesn't matter.
+	graph.AddEdge("x", "y", 1)
+	graph.AddEdge("y", "x", 1)
+
+	distances, prev, err := graph.LongestPathsFrom([]string{"a"})
+	if err != nil {
+		t.Fatalf("LongestPathsFrom() error = %v", err)
+	}
+	if want := map[string]int{"a": 0, "b": 1, "c": 4, "d": 8}; !maps.Equal(distances, want) {
+		t.Errorf("LongestPathsFrom() = %v, want %v", distances, want)
+	}
+	if prev["d"] != "b" || prev["c"] != "a" {
+		t.Errorf("LongestPathsFrom() prev = %v", prev)
+	}
+
+	if _, _, err := graph.LongestPathsFrom([]string{"x"}); !errors.Is(err, ErrCycle) {
+		t.Errorf("LongestPathsFrom(x) error = %v, want ErrCycle", err)
+	}
+}
@@ -1,6 +1,11 @@
 package utils
 
-import "log"
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"log"
+)
 
 type Direction int
 
@@ -11,19 +16,117 @@ const (
 	West
 )
 
-func (d Direction) Reverse() Direction {
+var directions4 = []Direction{North, East, South, West}
+
+var ErrInvalidDirection = errors.New("invalid direction")
+
+// A Stepper is any direction with a fixed (x, y) delta, so Direction, Direction8 and
+// HexDirection can all be passed to Position.Step and Neighbors.
+type Stepper interface {
+	MustDelta() (int, int)
+}
+
+func (d Direction) Valid() bool {
+	return d >= North && d <= West
+}
+
+// AllDirections yields North, East, South and West, in that order.
+func AllDirections() iter.Seq[Direction] {
+	return func(yield func(Direction) bool) {
+		for _, d := range directions4 {
+			if !yield(d) {
+				return
+			}
+		}
+	}
+}
+
+// TurnRight rotates 90 degrees clockwise. Invalid directions are returned unchanged.
+func (d Direction) TurnRight() Direction {
+	if !d.Valid() {
+		return d
+	}
+	return (d + 1) % 4
+}
+
+// TurnLeft rotates 90 degrees counterclockwise. Invalid directions are returned unchanged.
+func (d Direction) TurnLeft() Direction {
+	if !d.Valid() {
+		return d
+	}
+	return (d + 3) % 4
+}
+
+// ReflectSlash is the direction of travel after bouncing off a '/' mirror, so
+// North becomes East and West becomes South.
+func (d Direction) ReflectSlash() Direction {
+	if !d.Valid() {
+		return d
+	}
+	return d ^ 1
+}
+
+// ReflectBackslash is the direction of travel after bouncing off a '\' mirror, so
+// North becomes West and East becomes South.
+func (d Direction) ReflectBackslash() Direction {
+	if !d.Valid() {
+		return d
+	}
+	return West - d
+}
+
+// A DirectionAlphabet gives the character used for each Direction in some input format,
+// indexed by Direction.
+type DirectionAlphabet [4]rune
+
+var (
+	NESW       = DirectionAlphabet{'N', 'E', 'S', 'W'}
+	URDL       = DirectionAlphabet{'U', 'R', 'D', 'L'}
+	Arrows     = DirectionAlphabet{'^', '>', 'v', '<'}
+	DigitsRDLU = DirectionAlphabet{'3', '0', '1', '2'} // 0 means right, 1 down, 2 left, 3 up
+)
+
+func (a DirectionAlphabet) Rune(d Direction) rune {
+	return a[d]
+}
+
+func ParseDirection(r rune, alphabet DirectionAlphabet) (Direction, error) {
+	for d, c := range alphabet {
+		if c == r {
+			return Direction(d), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q is not one of %q", ErrInvalidDirection, r, string(alphabet[:]))
+}
+
+func MustParseDirection(r rune, alphabet DirectionAlphabet) Direction {
+	ret, err := ParseDirection(r, alphabet)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return ret
+}
+
+func (d Direction) Reverse() (Direction, error) {
 	switch d {
 	case North:
-		return South
+		return South, nil
 	case East:
-		return West
+		return West, nil
 	case South:
-		return North
+		return North, nil
 	case West:
-		return East
+		return East, nil
 	}
-	log.Fatal("Invalid direction", int(d))
-	return 0
+	return 0, fmt.Errorf("%w: %d", ErrInvalidDirection, int(d))
+}
+
+func (d Direction) MustReverse() Direction {
+	ret, err := d.Reverse()
+	if err != nil {
+		log.Fatal(err)
+	}
+	return ret
 }
 
 func (d Direction) String() string {
@@ -37,22 +140,28 @@ func (d Direction) String() string {
 	case West:
 		return "West"
 	}
-	log.Fatal("Invalid direction", int(d))
-	return ""
+	return fmt.Sprintf("Direction(%d)", int(d))
 }
 
 // returns DeltaX, DeltaY
-func (d Direction) Delta() (int, int) {
+func (d Direction) Delta() (int, int, error) {
 	switch d {
 	case North:
-		return 0, -1
+		return 0, -1, nil
 	case East:
-		return 1, 0
+		return 1, 0, nil
 	case South:
-		return 0, 1
+		return 0, 1, nil
 	case West:
-		return -1, 0
+		return -1, 0, nil
+	}
+	return 0, 0, fmt.Errorf("%w: %d", ErrInvalidDirection, int(d))
+}
+
+func (d Direction) MustDelta() (int, int) {
+	deltaX, deltaY, err := d.Delta()
+	if err != nil {
+		log.Fatal(err)
 	}
-	log.Fatal("Invalid direction", d)
-	return 0, 0
+	return deltaX, deltaY
 }
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+)
+
+// Direction8 adds the diagonals to Direction. The values go clockwise from North so
+// that rotating is just modular arithmetic.
+type Direction8 int
+
+const (
+	North8 Direction8 = iota
+	NorthEast8
+	East8
+	SouthEast8
+	South8
+	SouthWest8
+	West8
+	NorthWest8
+)
+
+var directions8 = []Direction8{North8, NorthEast8, East8, SouthEast8, South8, SouthWest8, West8, NorthWest8}
+
+func (d Direction) AsDirection8() Direction8 {
+	return Direction8(2 * int(d))
+}
+
+func (d Direction8) Valid() bool {
+	return d >= North8 && d <= NorthWest8
+}
+
+func (d Direction8) IsDiagonal() bool {
+	return d.Valid() && d%2 == 1
+}
+
+// Rotate45 turns clockwise by 45 degrees for each step; negative steps turn counterclockwise.
+func (d Direction8) Rotate45(steps int) Direction8 {
+	return Direction8(((int(d)+steps)%8 + 8) % 8)
+}
+
+func (d Direction8) Reverse() Direction8 {
+	return d.Rotate45(4)
+}
+
+func (d Direction8) String() string {
+	switch d {
+	case North8:
+		return "North"
+	case NorthEast8:
+		return "NorthEast"
+	case East8:
+		return "East"
+	case SouthEast8:
+		return "SouthEast"
+	case South8:
+		return "South"
+	case SouthWest8:
+		return "SouthWest"
+	case West8:
+		return "West"
+	case NorthWest8:
+		return "NorthWest"
+	}
+	return fmt.Sprintf("Direction8(%d)", int(d))
+}
+
+// returns DeltaX, DeltaY
+func (d Direction8) Delta() (int, int, error) {
+	switch d {
+	case North8:
+		return 0, -1, nil
+	case NorthEast8:
+		return 1, -1, nil
+	case East8:
+		return 1, 0, nil
+	case SouthEast8:
+		return 1, 1, nil
+	case South8:
+		return 0, 1, nil
+	case SouthWest8:
+		return -1, 1, nil
+	case West8:
+		return -1, 0, nil
+	case NorthWest8:
+		return -1, -1, nil
+	}
+	return 0, 0, fmt.Errorf("%w: %d", ErrInvalidDirection, int(d))
+}
+
+func (d Direction8) MustDelta() (int, int) {
+	deltaX, deltaY, err := d.Delta()
+	if err != nil {
+		log.Fatal(err)
+	}
+	return deltaX, deltaY
+}
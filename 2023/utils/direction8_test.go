@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDirection8Rotate45(t *testing.T) {
+	tests := []struct {
+		d     Direction8
+		steps int
+		want  Direction8
+	}{
+		{North8, 1, NorthEast8},
+		{North8, -1, NorthWest8},
+		{NorthWest8, 1, North8},
+		{East8, 2, South8},
+		{South8, -10, East8},
+		{SouthWest8, 8, SouthWest8},
+	}
+	for _, tt := range tests {
+		if got := tt.d.Rotate45(tt.steps); got != tt.want {
+			t.Errorf("%v.Rotate45(%d) = %v, want %v", tt.d, tt.steps, got, tt.want)
+		}
+	}
+}
+
+func TestDirection8Delta(t *testing.T) {
+	for _, d := range directions8 {
+		dx, dy := d.MustDelta()
+		rx, ry := d.Reverse().MustDelta()
+		if dx != -rx || dy != -ry {
+			t.Errorf("%v.Reverse() = %v, whose delta isn't the opposite", d, d.Reverse())
+		}
+		if d.IsDiagonal() != (dx != 0 && dy != 0) {
+			t.Errorf("%v.IsDiagonal() = %v for delta (%d, %d)", d, d.IsDiagonal(), dx, dy)
+		}
+	}
+	for _, d := range []Direction{North, East, South, West} {
+		if got, want := (Position{}).Step(d.AsDirection8(), 1), (Position{}).Step(d, 1); got != want {
+			t.Errorf("%v.AsDirection8() steps to %v, want %v", d, got, want)
+		}
+	}
+	if _, _, err := Direction8(9).Delta(); !errors.Is(err, ErrInvalidDirection) {
+		t.Errorf("Delta() error = %v, want ErrInvalidDirection", err)
+	}
+	if got := Direction8(9).String(); got != "Direction8(9)" {
+		t.Errorf("String() = %v, want Direction8(9)", got)
+	}
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestDirectionInvalid(t *testing.T) {
+	d := Direction(7)
+	if d.Valid() {
+		t.Error("Direction(7).Valid() = true, want false")
+	}
+	if _, err := d.Reverse(); !errors.Is(err, ErrInvalidDirection) {
+		t.Errorf("Reverse() error = %v, want ErrInvalidDirection", err)
+	}
+	if _, _, err := d.Delta(); !errors.Is(err, ErrInvalidDirection) {
+		t.Errorf("Delta() error = %v, want ErrInvalidDirection", err)
+	}
+	if got := d.String(); got != "Direction(7)" {
+		t.Errorf("String() = %v, want Direction(7)", got)
+	}
+}
+
+func TestDirectionReverse(t *testing.T) {
+	for _, d := range []Direction{North, East, South, West} {
+		r, err := d.Reverse()
+		if err != nil {
+			t.Fatalf("%v.Reverse() error = %v", d, err)
+		}
+		dx, dy := d.MustDelta()
+		rx, ry := r.MustDelta()
+		if dx != -rx || dy != -ry {
+			t.Errorf("%v.Reverse() = %v, whose delta isn't the opposite", d, r)
+		}
+	}
+}
+
+func TestDirectionTurns(t *testing.T) {
+	tests := []struct {
+		d                                           Direction
+		left, right, reflectSlash, reflectBackslash Direction
+	}{
+		{North, West, East, East, West},
+		{East, North, South, North, South},
+		{South, East, West, West, East},
+		{West, South, North, South, North},
+	}
+	for _, tt := range tests {
+		if got := tt.d.TurnLeft(); got != tt.left {
+			t.Errorf("%v.TurnLeft() = %v, want %v", tt.d, got, tt.left)
+		}
+		if got := tt.d.TurnRight(); got != tt.right {
+			t.Errorf("%v.TurnRight() = %v, want %v", tt.d, got, tt.right)
+		}
+		if got := tt.d.ReflectSlash(); got != tt.reflectSlash {
+			t.Errorf("%v.ReflectSlash() = %v, want %v", tt.d, got, tt.reflectSlash)
+		}
+		if got := tt.d.ReflectBackslash(); got != tt.reflectBackslash {
+			t.Errorf("%v.ReflectBackslash() = %v, want %v", tt.d, got, tt.reflectBackslash)
+		}
+	}
+	if got := Direction(7).TurnLeft(); got != Direction(7) {
+		t.Errorf("Direction(7).TurnLeft() = %v, want Direction(7)", got)
+	}
+}
+
+func TestParseDirection(t *testing.T) {
+	tests := []struct {
+		r        rune
+		alphabet DirectionAlphabet
+		want     Direction
+	}{
+		{'N', NESW, North},
+		{'W', NESW, West},
+		{'R', URDL, East},
+		{'D', URDL, South},
+		{'^', Arrows, North},
+		{'<', Arrows, West},
+		{'0', DigitsRDLU, East},
+		{'3', DigitsRDLU, North},
+	}
+	for _, tt := range tests {
+		got, err := ParseDirection(tt.r, tt.alphabet)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDirection(%q) = %v, %v, want %v", tt.r, got, err, tt.want)
+		}
+		if back := tt.alphabet.Rune(got); back != tt.r {
+			t.Errorf("Rune(%v) = %q, want %q", got, back, tt.r)
+		}
+	}
+	if _, err := ParseDirection('x', URDL); !errors.Is(err, ErrInvalidDirection) {
+		t.Errorf("ParseDirection('x') error = %v, want ErrInvalidDirection", err)
+	}
+	if got := slices.Collect(AllDirections()); !slices.Equal(got, []Direction{North, East, South, West}) {
+		t.Errorf("AllDirections() = %v", got)
+	}
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"iter"
+	"slices"
+)
+
+// DistancesFrom returns the distance from the nearest of sources to every node that
+// can be reached, and the node before each one on a shortest path to it. Sources
+// have no predecessor.
+func (graph *Graph[T]) DistancesFrom(sources []T) (map[T]int, map[T]T) {
+	return DistancesFromFunc(sources, graph.Neighbors)
+}
+
+// DistancesFromFunc is DistancesFrom over an implicit graph. It never stops on its own,
+// so the reachable part of the graph must be finite.
+func DistancesFromFunc[T comparable](sources []T, neighbors func(T) iter.Seq[Neighbor[T]]) (map[T]int, map[T]T) {
+	toVisit := NewIndexedHeap[T]()
+	distances := make(map[T]int)
+	for _, source := range sources {
+		toVisit.Push(source, 0)
+		distances[source] = 0
+	}
+	prev := make(map[T]T)
+
+	for toVisit.Len() > 0 {
+		curr, currDistance, _ := toVisit.PopMin()
+		for neighbor := range neighbors(curr) {
+			prevCost, hasPrevCost := distances[neighbor.Value]
+			newCostToNeighbor := currDistance + neighbor.Cost
+			if hasPrevCost && prevCost <= newCostToNeighbor {
+				continue
+			}
+			prev[neighbor.Value] = curr
+			distances[neighbor.Value] = newCostToNeighbor
+			if !toVisit.DecreaseKey(neighbor.Value, newCostToNeighbor) {
+				toVisit.Push(neighbor.Value, newCostToNeighbor)
+			}
+		}
+	}
+	return distances, prev
+}
+
+// BFSDistancesFrom is DistancesFrom for when every edge costs the same: costs are
+// ignored and distances count edges, which skips the priority queue entirely.
+func (graph *Graph[T]) BFSDistancesFrom(sources []T) (map[T]int, map[T]T) {
+	return BFSDistancesFromFunc(sources, func(node T) iter.Seq[T] {
+		return func(yield func(T) bool) {
+			for _, neighbor := range (*graph)[node] {
+				if !yield(neighbor.Value) {
+					return
+				}
+			}
+		}
+	})
+}
+
+// BFSDistancesFromFunc is BFSDistancesFrom over an implicit graph, which only has to
+// say what each node is next to.
+func BFSDistancesFromFunc[T comparable](sources []T, neighbors func(T) iter.Seq[T]) (map[T]int, map[T]T) {
+	distances := make(map[T]int)
+	prev := make(map[T]T)
+	toVisit := slices.Clone(sources)
+	for _, source := range sources {
+		distances[source] = 0
+	}
+	for len(toVisit) > 0 {
+		curr := toVisit[0]
+		toVisit = toVisit[1:]
+		for neighbor := range neighbors(curr) {
+			if _, seen := distances[neighbor]; seen {
+				continue
+			}
+			distances[neighbor] = distances[curr] + 1
+			prev[neighbor] = curr
+			toVisit = append(toVisit, neighbor)
+		}
+	}
+	return distances, prev
+}
+
+// FloodFill walks outwards from start, moving orthogonally onto cells for which passable
+// is true. It returns the number of steps to each cell, with -1 for the cells it never
+// reached. start itself is always reached, even if it isn't passable.
+func (grid Grid[T]) FloodFill(start Position, passable func(T) bool) Grid[int] {
+	distances := MapGrid(grid, func(T) int { return -1 })
+	distances.Set(start, 0)
+	toVisit := []Position{start}
+	for len(toVisit) > 0 {
+		curr := toVisit[0]
+		toVisit = toVisit[1:]
+		for _, next := range grid.Neighbors4(curr) {
+			if distances.ItemAt(next) >= 0 || !passable(grid.ItemAt(next)) {
+				continue
+			}
+			distances.Set(next, distances.ItemAt(curr)+1)
+			toVisit = append(toVisit, next)
+		}
+	}
+	return distances
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestDistancesFrom(t *testing.T) {
+	graph := make(Graph[string])
+	graph.AddEdge("a", "b", 1)
+	graph.AddEdge("b", "c", 2)
+	graph.AddEdge("a", "c", 5)
+	graph.AddEdge("c", "d", 1)
+	graph.AddEdge("x", "a", 1)
+
+	distances, prev := graph.DistancesFrom([]string{"a"})
+	if want := map[string]int{"a": 0, "b": 1, "c": 3, "d": 4}; !maps.Equal(distances, want) {
+		t.Errorf("DistancesFrom() distances = %v, want %v", distances, want)
+	}
+	if want := map[string]string{"b": "a", "c": "b", "d": "c"}; !maps.Equal(prev, want) {
+		t.Errorf("DistancesFrom() prev = %v, want %v", prev, want)
+	}
+
+	distances, prev = graph.BFSDistancesFrom([]string{"a"})
+	if want := map[string]int{"a": 0, "b": 1, "c": 1, "d": 2}; !maps.Equal(distances, want) {
+		t.Errorf("BFSDistancesFrom() distances = %v, want %v", distances, want)
+	}
+	if prev["c"] != "a" {
+		t.Errorf("BFSDistancesFrom() prev[c] = %v, want a", prev["c"])
+	}
+
+	distances, _ = graph.DistancesFrom([]string{"x", "c"})
+	if want := map[string]int{"x": 0, "a": 1, "b": 2, "c": 0, "d": 1}; !maps.Equal(distances, want) {
+		t.Errorf("DistancesFrom() with two sources = %v, want %v", distances, want)
+	}
+}
+
+func TestFloodFill(t *testing.T) {
+	grid := Grid[rune]{
+		[]rune("..#."),
+		[]rune(".##."),
+		[]rune("...."),
+		[]rune("###."),
+	}
+	distances := grid.FloodFill(Position{X: 0, Y: 0}, func(r rune) bool { return r == '.' })
+	want := Grid[int]{
+		{0, 1, -1, 7},
+		{1, -1, -1, 6},
+		{2, 3, 4, 5},
+		{-1, -1, -1, 6},
+	}
+	if !distances.Equal(want) {
+		t.Errorf("FloodFill() =\n%v\nwant\n%v", distances, want)
+	}
+}
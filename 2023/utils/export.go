@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DOTOptions controls how WriteDOT draws a graph. The zero value labels nodes with
+// fmt.Sprint and edges with their cost.
+type DOTOptions[T comparable] struct {
+	// Name is the name of the digraph; "G" if empty.
+	Name string
+	// NodeLabel, if set, replaces fmt.Sprint for node labels.
+	NodeLabel func(T) string
+	// EdgeLabel, if set, replaces the cost as the edge label. Return "" for no label.
+	EdgeLabel func(Edge[T]) string
+	// Highlight lists paths whose nodes and edges are drawn in red.
+	Highlight [][]T
+}
+
+// WriteDOT writes the graph in Graphviz's DOT language, e.g. for `dot -Tsvg`. Nodes
+// and edges are sorted by label so the output is stable.
+func (graph *Graph[T]) WriteDOT(w io.Writer, opts DOTOptions[T]) error {
+	nodeLabel := opts.NodeLabel
+	if nodeLabel == nil {
+		nodeLabel = func(node T) string { return fmt.Sprint(node) }
+	}
+	edgeLabel := opts.EdgeLabel
+	if edgeLabel == nil {
+		edgeLabel = func(edge Edge[T]) string { return strconv.Itoa(edge.Cost) }
+	}
+	name := opts.Name
+	if name == "" {
+		name = "G"
+	}
+
+	highlightedNodes := make(map[T]bool)
+	highlightedEdges := make(map[[2]T]bool)
+	for _, path := range opts.Highlight {
+		for i, node := range path {
+			highlightedNodes[node] = true
+			if i > 0 {
+				highlightedEdges[[2]T{path[i-1], node}] = true
+			}
+		}
+	}
+
+	nodes := graph.Nodes()
+	labels := make(map[T]string, len(nodes))
+	for _, node := range nodes {
+		labels[node] = nodeLabel(node)
+	}
+	slices.SortFunc(nodes, func(a, b T) int { return cmp.Compare(labels[a], labels[b]) })
+	ids := make(map[T]string, len(nodes))
+	for i, node := range nodes {
+		ids[node] = fmt.Sprintf("n%d", i)
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph %s {\n", strconv.Quote(name))
+	for _, node := range nodes {
+		attrs := []string{"label=" + strconv.Quote(labels[node])}
+		if highlightedNodes[node] {
+			attrs = append(attrs, `color="red"`, `penwidth=2`)
+		}
+		fmt.Fprintf(bw, "  %s [%s];\n", ids[node], strings.Join(attrs, ", "))
+	}
+	edges := graph.Edges()
+	slices.SortFunc(edges, func(a, b Edge[T]) int {
+		return cmp.Or(cmp.Compare(ids[a.From], ids[b.From]), cmp.Compare(ids[a.To], ids[b.To]), cmp.Compare(a.Cost, b.Cost))
+	})
+	for _, edge := range edges {
+		attrs := make([]string, 0)
+		if label := edgeLabel(edge); label != "" {
+			attrs = append(attrs, "label="+strconv.Quote(label))
+		}
+		if highlightedEdges[[2]T{edge.From, edge.To}] {
+			attrs = append(attrs, `color="red"`, `penwidth=2`)
+		}
+		if len(attrs) > 0 {
+			fmt.Fprintf(bw, "  %s -> %s [%s];\n", ids[edge.From], ids[edge.To], strings.Join(attrs, ", "))
+		} else {
+			fmt.Fprintf(bw, "  %s -> %s;\n", ids[edge.From], ids[edge.To])
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+type jsonNeighbor[T comparable] struct {
+	To   T   `json:"to"`
+	Cost int `json:"cost"`
+}
+
+type jsonNode[T comparable] struct {
+	Node  T                 `json:"node"`
+	Edges []jsonNeighbor[T] `json:"edges"`
+}
+
+// MarshalJSON writes the graph as a list of {"node": ..., "edges": [{"to": ..., "cost": ...}]},
+// which works for any node type encoding/json can handle, not just strings. Every node
+// is listed, including ones with no outgoing edges, sorted by their JSON encoding. It has
+// a value receiver so that json.Marshal uses it for plain Graph values too.
+func (graph Graph[T]) MarshalJSON() ([]byte, error) {
+	nodes := graph.Nodes()
+	keys := make(map[T]string, len(nodes))
+	for _, node := range nodes {
+		key, err := json.Marshal(node)
+		if err != nil {
+			return nil, err
+		}
+		keys[node] = string(key)
+	}
+	slices.SortFunc(nodes, func(a, b T) int { return cmp.Compare(keys[a], keys[b]) })
+
+	adjacency := make([]jsonNode[T], len(nodes))
+	for i, node := range nodes {
+		adjacency[i] = jsonNode[T]{Node: node, Edges: make([]jsonNeighbor[T], 0)}
+		for _, neighbor := range graph[node] {
+			adjacency[i].Edges = append(adjacency[i].Edges, jsonNeighbor[T]{To: neighbor.Value, Cost: neighbor.Cost})
+		}
+	}
+	return json.Marshal(adjacency)
+}
+
+// UnmarshalJSON reads the format written by MarshalJSON, replacing the graph's contents.
+func (graph *Graph[T]) UnmarshalJSON(data []byte) error {
+	var adjacency []jsonNode[T]
+	if err := json.Unmarshal(data, &adjacency); err != nil {
+		return err
+	}
+	*graph = make(Graph[T])
+	for _, node := range adjacency {
+		(*graph)[node.Node] = make([]Neighbor[T], 0, len(node.Edges))
+		for _, edge := range node.Edges {
+			graph.AddEdge(node.Node, edge.To, edge.Cost)
+		}
+	}
+	return nil
+}
+
+func (graph *Graph[T]) WriteJSON(w io.Writer) error {
+	data, err := graph.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func ReadGraphJSON[T comparable](r io.Reader) (Graph[T], error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var graph Graph[T]
+	if err := graph.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
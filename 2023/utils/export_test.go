@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	graph := make(Graph[string])
+	graph.AddEdge("a", "b", 1)
+	graph.AddEdge("b", "c", 2)
+	graph.AddEdge("a", "c", 5)
+	graph.AddEdge("c", "d\"quoted", 1)
+
+	var buf bytes.Buffer
+	if err := graph.WriteDOT(&buf, DOTOptions[string]{}); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph "G" {
+  n0 [label="a"];
+  n1 [label="b"];
+  n2 [label="c"];
+  n3 [label="d\"quoted"];
+  n0 -> n1 [label="1"];
+  n0 -> n2 [label="5"];
+  n1 -> n2 [label="2"];
+  n2 -> n3 [label="1"];
+}
+`
+	if buf.String() != want {
+		t.Errorf("WriteDOT() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	err := graph.WriteDOT(&buf, DOTOptions[string]{
+		Name:      "path",
+		NodeLabel: strings.ToUpper,
+		EdgeLabel: func(Edge[string]) string { return "" },
+		Highlight: [][]string{{"a", "b", "c"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		`digraph "path" {`,
+		`  n0 [label="A", color="red", penwidth=2];`,
+		`  n3 [label="D\"QUOTED"];`,
+		`  n0 -> n1 [color="red", penwidth=2];`,
+		`  n0 -> n2;`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("WriteDOT() with options is missing %q:\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestGraphJSON(t *testing.T) {
+	graph := make(Graph[Position])
+	graph.AddEdge(Position{X: 0, Y: 0}, Position{X: 1, Y: 0}, 3)
+	graph.AddEdge(Position{X: 1, Y: 0}, Position{X: 1, Y: -1}, 4)
+	graph.AddEdge(Position{X: 0, Y: 0}, Position{X: 1, Y: -1}, 9)
+
+	var buf bytes.Buffer
+	if err := graph.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"node":{"X":0,"Y":0},"edges":[{"to":{"X":1,"Y":0},"cost":3},{"to":{"X":1,"Y":-1},"cost":9}]},` +
+		`{"node":{"X":1,"Y":-1},"edges":[]},` +
+		`{"node":{"X":1,"Y":0},"edges":[{"to":{"X":1,"Y":-1},"cost":4}]}]`
+	if buf.String() != want {
+		t.Errorf("WriteJSON() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	read, err := ReadGraphJSON[Position](&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != 3 || len(read.Edges()) != 3 {
+		t.Errorf("ReadGraphJSON() = %v", read.String())
+	}
+	distance, _, err := read.FindDistanceAndPath([]Position{{X: 0, Y: 0}}, []Position{{X: 1, Y: -1}})
+	if err != nil || distance != 7 {
+		t.Errorf("FindDistanceAndPath() on the read graph = %v, %v, want 7", distance, err)
+	}
+
+	// it also works through encoding/json directly.
+	data, err := json.Marshal(&graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Graph[Position]
+	if err := json.Unmarshal(data, &decoded); err != nil || len(decoded) != 3 {
+		t.Errorf("json.Unmarshal() = %v, %v", decoded.String(), err)
+	}
+	// and for a plain value, which would otherwise be encoded as a map and fail on the
+	// Position keys.
+	data, err = json.Marshal(graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != want {
+		t.Errorf("json.Marshal() =\n%s\nwant\n%s", data, want)
+	}
+
+	if _, err := ReadGraphJSON[string](strings.NewReader(`{"not": "a list"}`)); err == nil {
+		t.Error("ReadGraphJSON() of an object succeeded")
+	}
+}
@@ -1,8 +1,9 @@
 package utils
 
 import (
-	"container/heap"
+	"errors"
 	"fmt"
+	"iter"
 	"log"
 	"slices"
 	"strings"
@@ -14,6 +15,8 @@ type Neighbor[T comparable] struct {
 }
 type PositionGraph = Graph[Position]
 
+var ErrNoPath = errors.New("no path found")
+
 type Graph[T comparable] map[T][]Neighbor[T]
 
 func (graph *Graph[T]) String() string {
@@ -29,6 +32,29 @@ func (graph *Graph[T]) AddEdge(from T, to T, Cost int) {
 	(*graph)[from] = append((*graph)[from], Neighbor[T]{Value: to, Cost: Cost})
 }
 
+// AddUndirectedEdge adds the edge in both directions.
+func (graph *Graph[T]) AddUndirectedEdge(a T, b T, Cost int) {
+	graph.AddEdge(a, b, Cost)
+	graph.AddEdge(b, a, Cost)
+}
+
+type Edge[T comparable] struct {
+	From T
+	To   T
+	Cost int
+}
+
+// Edges lists every edge in the graph, in no particular order.
+func (graph *Graph[T]) Edges() []Edge[T] {
+	ret := make([]Edge[T], 0)
+	for from, tos := range *graph {
+		for _, to := range tos {
+			ret = append(ret, Edge[T]{From: from, To: to.Value, Cost: to.Cost})
+		}
+	}
+	return ret
+}
+
 func makePathFromPrevMap[T comparable](prev map[T]T, froms []T, to T) [][]T {
 	ret := make([][]T, 0)
 	for _, from := range froms {
@@ -49,83 +75,86 @@ func makePathFromPrevMap[T comparable](prev map[T]T, froms []T, to T) [][]T {
 	return ret
 }
 
-func (graph *Graph[T]) FindDistanceAndPath(froms []T, ends []T) (int, [][]T) {
+// Neighbors yields the edges out of node, for use with the *Func searches.
+func (graph *Graph[T]) Neighbors(node T) iter.Seq[Neighbor[T]] {
+	return slices.Values((*graph)[node])
+}
+
+// FindDistanceAndPath returns ErrNoPath if none of the ends can be reached from froms.
+func (graph *Graph[T]) FindDistanceAndPath(froms []T, ends []T) (int, [][]T, error) {
 	//fmt.Println("Finding path from", froms, "to", ends)
-	toVisit := make(PriorityQueue[T], len(froms))
+	return FindDistanceAndPathFunc(froms, graph.Neighbors, func(node T) bool { return slices.Contains(ends, node) })
+}
+
+// FindDistanceAndPathFunc is FindDistanceAndPath over a graph that's never built:
+// neighbors is called as each node is visited, and the search stops at the first
+// node for which isGoal is true.
+func FindDistanceAndPathFunc[T comparable](froms []T, neighbors func(T) iter.Seq[Neighbor[T]], isGoal func(T) bool) (int, [][]T, error) {
+	distance, paths, _, err := FindDistanceAndPathAStarFunc(froms, neighbors, isGoal, nil)
+	return distance, paths, err
+}
+
+// FindDistanceAndPathAStar is FindDistanceAndPath guided by heuristic, which estimates
+// the remaining cost from a node to the nearest of ends. The heuristic must never
+// overestimate or the distance may not be the shortest. It also returns how many nodes
+// were expanded; a nil heuristic is plain Dijkstra, which makes for an easy comparison.
+func (graph *Graph[T]) FindDistanceAndPathAStar(froms []T, ends []T, heuristic func(T) int) (int, [][]T, int, error) {
+	return FindDistanceAndPathAStarFunc(froms, graph.Neighbors, func(node T) bool { return slices.Contains(ends, node) }, heuristic)
+}
+
+// FindDistanceAndPathAStarFunc is FindDistanceAndPathAStar over an implicit graph, like
+// FindDistanceAndPathFunc.
+func FindDistanceAndPathAStarFunc[T comparable](froms []T, neighbors func(T) iter.Seq[Neighbor[T]], isGoal func(T) bool, heuristic func(T) int) (int, [][]T, int, error) {
+	if heuristic == nil {
+		heuristic = func(T) int { return 0 }
+	}
+	// the heap is ordered by distance + heuristic, while distances holds just the
+	// distance from the nearest of froms.
+	toVisit := NewIndexedHeap[T]()
 	distances := make(map[T]int)
-	for idx, from := range froms {
-		toVisit[idx] = &Item[T]{value: from, priority: 0}
+	for _, from := range froms {
+		toVisit.Push(from, heuristic(from))
 		distances[from] = 0
 	}
-	heap.Init(&toVisit)
 	prev := make(map[T]T)
+	expanded := 0
 
 	for toVisit.Len() > 0 {
-		currItem := heap.Pop(&toVisit).(*Item[T])
-		currPos := currItem.value
-		currDistance := currItem.priority
+		currPos, _, _ := toVisit.PopMin()
+		currDistance := distances[currPos]
+		expanded++
 
 		//log.Printf("Visiting %v with cost %d ", currPos, currDistance)
 
-		if slices.Contains(ends, currPos) {
+		if isGoal(currPos) {
 			pathsToCurr := makePathFromPrevMap(prev, froms, currPos)
-			return currDistance, pathsToCurr
+			return currDistance, pathsToCurr, expanded, nil
 		}
 
-		for _, neighbor := range (*graph)[currPos] {
+		for neighbor := range neighbors(currPos) {
 			prevCost, hasPrevCost := distances[neighbor.Value]
-			itemInToVisit := toVisit.Get(neighbor.Value)
 			newCostToNeighbor := currDistance + neighbor.Cost
-			if hasPrevCost && prevCost < newCostToNeighbor {
-				continue
-			}
-			if itemInToVisit != nil && itemInToVisit.priority < newCostToNeighbor {
+			if hasPrevCost && prevCost <= newCostToNeighbor {
 				continue
 			}
 
 			prev[neighbor.Value] = currPos
 			distances[neighbor.Value] = newCostToNeighbor
 			//log.Printf("Adding %v to toVisit with cost %d", neighbor.Value, currDistance+neighbor.Cost)
-			if itemInToVisit != nil {
-				toVisit.update(itemInToVisit, neighbor.Value, newCostToNeighbor)
-			} else {
-				heap.Push(&toVisit, &Item[T]{value: neighbor.Value, priority: currDistance + neighbor.Cost})
+			estimate := newCostToNeighbor + heuristic(neighbor.Value)
+			if !toVisit.DecreaseKey(neighbor.Value, estimate) {
+				toVisit.Push(neighbor.Value, estimate)
 			}
 		}
 	}
-	log.Fatal("No path found", graph)
-	return -1, nil
+	return -1, nil, expanded, ErrNoPath
 }
 
-func (graph *Graph[T]) FindCycleBFS(from T) []T {
-	visited := make(map[T]bool)
-	pathsToNode := make(map[T][]T)
-	toVisit := make([]T, 0)
-	toVisit = append(toVisit, from)
-	for len(toVisit) > 0 {
-		curr := toVisit[len(toVisit)-1]
-
-		pathToCurr := pathsToNode[curr]
-
-		toVisit = toVisit[:len(toVisit)-1]
-		if curr == from && len(pathToCurr) > 0 {
-			return pathToCurr
-		}
-
-		for _, neighbor := range (*graph)[curr] {
-			if visited[neighbor.Value] && neighbor.Value != from {
-				// Don't go backwards along the path
-				continue
-			}
-
-			toVisit = append(toVisit, neighbor.Value)
-			pathsToNode[neighbor.Value] = make([]T, len(pathsToNode[curr]))
-			copy(pathsToNode[neighbor.Value], pathsToNode[curr])
-			pathsToNode[neighbor.Value] = append(pathsToNode[neighbor.Value], curr)
-		}
-
-		visited[curr] = true
+func (graph *Graph[T]) MustFindDistanceAndPath(froms []T, ends []T) (int, [][]T) {
+	distance, paths, err := graph.FindDistanceAndPath(froms, ends)
+	if err != nil {
+		// printing the whole graph is unreadable; use WriteDOT to look at it instead.
+		log.Fatalf("%v from %v to %v in a graph of %d nodes", err, froms, ends, len(*graph))
 	}
-	log.Fatal("No path found", graph)
-	return nil
+	return distance, paths
 }
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"errors"
+	"iter"
+	"slices"
+	"testing"
+)
+
+func TestFindDistanceAndPath(t *testing.T) {
+	graph := make(Graph[string])
+	graph.AddEdge("a", "b", 1)
+	graph.AddEdge("b", "c", 2)
+	graph.AddEdge("a", "c", 5)
+	graph.AddEdge("d", "a", 1)
+
+	distance, paths, err := graph.FindDistanceAndPath([]string{"a"}, []string{"c"})
+	if err != nil || distance != 3 {
+		t.Fatalf("FindDistanceAndPath() = %v, %v, want 3, nil", distance, err)
+	}
+	if len(paths) != 1 || len(paths[0]) != 3 {
+		t.Errorf("FindDistanceAndPath() paths = %v, want [[a b c]]", paths)
+	}
+
+	if _, _, err := graph.FindDistanceAndPath([]string{"a"}, []string{"d"}); !errors.Is(err, ErrNoPath) {
+		t.Errorf("FindDistanceAndPath() error = %v, want ErrNoPath", err)
+	}
+}
+
+func TestFindDistanceAndPathFunc(t *testing.T) {
+	// an unbounded number line where stepping right and doubling both cost 1.
+	neighbors := func(n int) iter.Seq[Neighbor[int]] {
+		return slices.Values([]Neighbor[int]{{Value: n + 1, Cost: 1}, {Value: n * 2, Cost: 1}})
+	}
+	distance, paths, err := FindDistanceAndPathFunc([]int{1}, neighbors, func(n int) bool { return n == 20 })
+	if err != nil || distance != 5 {
+		t.Fatalf("FindDistanceAndPathFunc() = %v, %v, want 5, nil", distance, err)
+	}
+	if want := []int{1, 2, 4, 5, 10, 20}; len(paths) != 1 || !slices.Equal(paths[0], want) {
+		t.Errorf("FindDistanceAndPathFunc() paths = %v, want [%v]", paths, want)
+	}
+
+	none := func(n int) iter.Seq[Neighbor[int]] {
+		if n >= 3 {
+			return slices.Values([]Neighbor[int](nil))
+		}
+		return slices.Values([]Neighbor[int]{{Value: n + 1, Cost: 1}})
+	}
+	if _, _, err := FindDistanceAndPathFunc([]int{0}, none, func(n int) bool { return n == 5 }); !errors.Is(err, ErrNoPath) {
+		t.Errorf("FindDistanceAndPathFunc() error = %v, want ErrNoPath", err)
+	}
+}
+
+func TestFindDistanceAndPathAStar(t *testing.T) {
+	grid := NewGrid(20, 20, 1)
+	for y := range 15 {
+		grid.Set(Position{X: 10, Y: y}, 100)
+	}
+	graph := make(Graph[Position])
+	for pos := range grid.All() {
+		for _, next := range grid.Neighbors4(pos) {
+			graph.AddEdge(pos, next, grid.ItemAt(next))
+		}
+	}
+	froms := []Position{{X: 0, Y: 0}, {X: 0, Y: 19}}
+	ends := []Position{{X: 19, Y: 0}, {X: 19, Y: 5}}
+	heuristic := func(p Position) int {
+		return min(p.ManhattanDistance(ends[0]), p.ManhattanDistance(ends[1]))
+	}
+
+	dijkstraDistance, _, dijkstraExpanded, err := graph.FindDistanceAndPathAStar(froms, ends, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	distance, paths, expanded, err := graph.FindDistanceAndPathAStar(froms, ends, heuristic)
+	if err != nil || distance != dijkstraDistance {
+		t.Fatalf("FindDistanceAndPathAStar() = %v, %v, want %v, nil", distance, err, dijkstraDistance)
+	}
+	if expanded >= dijkstraExpanded {
+		t.Errorf("A* expanded %d nodes, dijkstra %d; want fewer", expanded, dijkstraExpanded)
+	}
+	if len(paths) != len(froms) {
+		t.Errorf("FindDistanceAndPathAStar() returned %d paths, want one per from", len(paths))
+	}
+}
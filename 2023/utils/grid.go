@@ -2,23 +2,51 @@ package utils
 
 import (
 	"bufio"
+	"errors"
+	"fmt"
+	"iter"
 	"log"
 	"os"
 	"slices"
 	"strings"
 )
 
-type Grid [][]rune
+// A Grid is indexed as grid[y][x]. Most puzzles read it as runes and then use
+// ParseGrid or MapGrid to turn it into something more convenient.
+type Grid[T comparable] [][]T
 
-func (grid Grid) ItemAt(pos Position) rune {
+func NewGrid[T comparable](width int, height int, fill T) Grid[T] {
+	grid := make(Grid[T], height)
+	for y := range grid {
+		grid[y] = slices.Repeat([]T{fill}, width)
+	}
+	return grid
+}
+
+func (grid Grid[T]) Width() int {
+	if len(grid) == 0 {
+		return 0
+	}
+	return len(grid[0])
+}
+
+func (grid Grid[T]) Height() int {
+	return len(grid)
+}
+
+func (grid Grid[T]) InBounds(pos Position) bool {
+	return pos.Y >= 0 && pos.Y < len(grid) && pos.X >= 0 && pos.X < len(grid[pos.Y])
+}
+
+func (grid Grid[T]) ItemAt(pos Position) T {
 	return grid[pos.Y][pos.X]
 }
 
-func (grid Grid) Set(pos Position, value rune) {
+func (grid Grid[T]) Set(pos Position, value T) {
 	grid[pos.Y][pos.X] = value
 }
 
-func (grid Grid) Count(r rune) int {
+func (grid Grid[T]) Count(r T) int {
 	count := 0
 	for _, row := range grid {
 		for _, v := range row {
@@ -30,10 +58,71 @@ func (grid Grid) Count(r rune) int {
 	return count
 }
 
-func (grid Grid) Transpose() Grid {
-	transposed := make(Grid, len(grid[0]))
+// All iterates over every cell in row-major order.
+func (grid Grid[T]) All() iter.Seq2[Position, T] {
+	return func(yield func(Position, T) bool) {
+		for y, row := range grid {
+			for x, v := range row {
+				if !yield(Position{X: x, Y: y}, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (grid Grid[T]) Row(y int) iter.Seq2[Position, T] {
+	return func(yield func(Position, T) bool) {
+		for x, v := range grid[y] {
+			if !yield(Position{X: x, Y: y}, v) {
+				return
+			}
+		}
+	}
+}
+
+func (grid Grid[T]) Col(x int) iter.Seq2[Position, T] {
+	return func(yield func(Position, T) bool) {
+		for y := range grid {
+			if !yield(Position{X: x, Y: y}, grid[y][x]) {
+				return
+			}
+		}
+	}
+}
+
+// Neighbors4 yields the in-bounds orthogonal neighbors of pos along with the direction
+// taken to reach each one.
+func (grid Grid[T]) Neighbors4(pos Position) iter.Seq2[Direction, Position] {
+	return Neighbors(grid, pos, directions4)
+}
+
+// Neighbors8 is like Neighbors4 but includes the diagonals.
+func (grid Grid[T]) Neighbors8(pos Position) iter.Seq2[Direction8, Position] {
+	return Neighbors(grid, pos, directions8)
+}
+
+// NeighborsHex treats the grid as a hex map in axial coordinates; see HexPosition.Position.
+func (grid Grid[T]) NeighborsHex(pos Position) iter.Seq2[HexDirection, Position] {
+	return Neighbors(grid, pos, hexDirections)
+}
+
+// Neighbors yields the in-bounds positions one step from pos in each of dirs.
+func Neighbors[D Stepper, T comparable](grid Grid[T], pos Position, dirs []D) iter.Seq2[D, Position] {
+	return func(yield func(D, Position) bool) {
+		for _, d := range dirs {
+			next := pos.Step(d, 1)
+			if grid.InBounds(next) && !yield(d, next) {
+				return
+			}
+		}
+	}
+}
+
+func (grid Grid[T]) Transpose() Grid[T] {
+	transposed := make(Grid[T], len(grid[0]))
 	for i := range transposed {
-		transposed[i] = make([]rune, len(grid))
+		transposed[i] = make([]T, len(grid))
 	}
 	for y, row := range grid {
 		for x, r := range row {
@@ -43,7 +132,7 @@ func (grid Grid) Transpose() Grid {
 	return transposed
 }
 
-func (grid Grid) Equal(other Grid) bool {
+func (grid Grid[T]) Equal(other Grid[T]) bool {
 	if len(grid) != len(other) {
 		return false
 	}
@@ -55,37 +144,130 @@ func (grid Grid) Equal(other Grid) bool {
 	return true
 }
 
-func (grid Grid) Clone() Grid {
-	clone := make(Grid, len(grid))
+func (grid Grid[T]) Clone() Grid[T] {
+	clone := make(Grid[T], len(grid))
 	for y, row := range grid {
-		clone[y] = make([]rune, len(row))
+		clone[y] = make([]T, len(row))
 		copy(clone[y], row)
 	}
 	return clone
 }
 
-func (grid Grid) String() string {
+func (grid Grid[T]) String() string {
 	var sb strings.Builder
 	for _, row := range grid {
-		sb.WriteString(string(row))
+		for _, v := range row {
+			switch v := any(v).(type) {
+			case rune:
+				sb.WriteRune(v)
+			case bool:
+				if v {
+					sb.WriteRune('#')
+				} else {
+					sb.WriteRune('.')
+				}
+			default:
+				fmt.Fprint(&sb, v)
+			}
+		}
 		sb.WriteString("\n")
 	}
 	return sb.String()
 }
 
-func ReadGridFromFile(fname string) Grid {
-	file, err := os.Open(fname)
+// MapGrid is a function rather than a method because methods can't introduce new
+// type parameters.
+func MapGrid[T comparable, U comparable](grid Grid[T], f func(T) U) Grid[U] {
+	ret := make(Grid[U], len(grid))
+	for y, row := range grid {
+		ret[y] = make([]U, len(row))
+		for x, v := range row {
+			ret[y][x] = f(v)
+		}
+	}
+	return ret
+}
+
+// ParseGrid converts each rune with parse, reporting the position of the first rune
+// that fails as a ParseError.
+func ParseGrid[T comparable](grid Grid[rune], parse func(rune) (T, error)) (Grid[T], error) {
+	ret := make(Grid[T], len(grid))
+	for y, row := range grid {
+		ret[y] = make([]T, len(row))
+		for x, r := range row {
+			v, err := parse(r)
+			if err != nil {
+				return nil, &ParseError{Line: y + 1, Column: x + 1, Err: err}
+			}
+			ret[y][x] = v
+		}
+	}
+	return ret, nil
+}
+
+func ReadGridFromFileAs[T comparable](fname string, parse func(rune) (T, error)) (Grid[T], error) {
+	grid, err := ReadGridFromFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := ParseGrid(grid, parse)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fname, err)
+	}
+	return ret, nil
+}
+
+func MustReadGridFromFileAs[T comparable](fname string, parse func(rune) (T, error)) Grid[T] {
+	grid, err := ReadGridFromFileAs(fname, parse)
 	if err != nil {
 		log.Fatal(err)
 	}
+	return grid
+}
+
+var ErrNotADigit = errors.New("not a digit")
+
+// ParseDigit is a parse function for grids of single decimal digits.
+func ParseDigit(r rune) (int, error) {
+	if r < '0' || r > '9' {
+		return 0, fmt.Errorf("%w: %q", ErrNotADigit, r)
+	}
+	return int(r - '0'), nil
+}
+
+var ErrRaggedGrid = errors.New("grid row has a different width than the first row")
+
+func ReadGridFromFile(fname string) (Grid[rune], error) {
+	file, err := os.Open(fname)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
 	scanner := bufio.NewScanner(file)
-	_, grid := ReadGridFromFD(scanner)
+	lineNum := 0
+	grid, err := readGrid(scanner, &lineNum)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fname, err)
+	}
+	return grid, nil
+}
+
+func MustReadGridFromFile(fname string) Grid[rune] {
+	grid, err := ReadGridFromFile(fname)
+	if err != nil {
+		log.Fatal(err)
+	}
 	return grid
 }
 
-func ReadGridFromFD(scanner *bufio.Scanner) (bool, Grid) {
-	grid := make(Grid, 0)
+// readGrid reads rows until a blank line or EOF. It always consumes the whole block so
+// that a caller reading several grids stays in sync, and reports the first row whose
+// width differs from the first one. lineNum counts lines consumed across calls.
+func readGrid(scanner *bufio.Scanner, lineNum *int) (Grid[rune], error) {
+	grid := make(Grid[rune], 0)
+	var firstErr error
 	for scanner.Scan() {
+		*lineNum++
 		t := strings.TrimSpace(scanner.Text())
 		if t == "" {
 			break
@@ -94,24 +276,49 @@ func ReadGridFromFD(scanner *bufio.Scanner) (bool, Grid) {
 		for _, r := range t {
 			row = append(row, r)
 		}
+		if firstErr == nil && len(grid) > 0 && len(row) != len(grid[0]) {
+			firstErr = &ParseError{Line: *lineNum, Column: min(len(row), len(grid[0])) + 1, Err: ErrRaggedGrid}
+		}
 		grid = append(grid, row)
 	}
+	if err := scanner.Err(); err != nil {
+		return grid, err
+	}
+	return grid, firstErr
+}
+
+func ReadGridFromFD(scanner *bufio.Scanner) (bool, Grid[rune]) {
+	lineNum := 0
+	grid, _ := readGrid(scanner, &lineNum)
 	return len(grid) > 0, grid
 }
 
-func ReadGridsFromFile(fname string) []Grid {
+func ReadGridsFromFile(fname string) ([]Grid[rune], error) {
 	file, err := os.Open(fname)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
+	defer file.Close()
 	scanner := bufio.NewScanner(file)
-	grids := make([]Grid, 0)
+	grids := make([]Grid[rune], 0)
+	lineNum := 0
 	for {
-		ok, grid := ReadGridFromFD(scanner)
-		if !ok {
+		grid, err := readGrid(scanner, &lineNum)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fname, err)
+		}
+		if len(grid) == 0 {
 			break
 		}
 		grids = append(grids, grid)
 	}
+	return grids, nil
+}
+
+func MustReadGridsFromFile(fname string) []Grid[rune] {
+	grids, err := ReadGridsFromFile(fname)
+	if err != nil {
+		log.Fatal(err)
+	}
 	return grids
 }
@@ -2,6 +2,10 @@ package utils
 
 import (
 	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -9,11 +13,11 @@ import (
 func TestReadGridFromFD(t *testing.T) {
 	type Test struct {
 		in   string
-		want Grid
+		want Grid[rune]
 	}
 	tests := []Test{{
 		in: "123\n456\n789",
-		want: Grid{
+		want: Grid[rune]{
 			{'1', '2', '3'},
 			{'4', '5', '6'},
 			{'7', '8', '9'},
@@ -29,3 +33,109 @@ func TestReadGridFromFD(t *testing.T) {
 		})
 	}
 }
+
+func TestReadGridFromFileRagged(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "grid.txt")
+	if err := os.WriteFile(fname, []byte("123\n456\n78\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := ReadGridFromFile(fname)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || !errors.Is(err, ErrRaggedGrid) {
+		t.Fatalf("ReadGridFromFile() error = %v, want a ParseError wrapping ErrRaggedGrid", err)
+	}
+	if parseErr.Line != 3 || parseErr.Column != 3 {
+		t.Errorf("ReadGridFromFile() error at line %d, column %d, want line 3, column 3", parseErr.Line, parseErr.Column)
+	}
+}
+
+func TestReadGridsFromFileLineNumbers(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "grids.txt")
+	if err := os.WriteFile(fname, []byte("12\n34\n\n12\n345\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := ReadGridsFromFile(fname)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Line != 5 {
+		t.Errorf("ReadGridsFromFile() error = %v, want a ParseError on line 5", err)
+	}
+	if _, err := ReadGridsFromFile(filepath.Join(t.TempDir(), "missing.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ReadGridsFromFile() error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestGridNeighbors(t *testing.T) {
+	grid := NewGrid(3, 2, 0)
+	var got4 []Position
+	for d, p := range grid.Neighbors4(Position{X: 0, Y: 0}) {
+		dx, dy := d.MustDelta()
+		if p.X != dx || p.Y != dy {
+			t.Errorf("Neighbors4 yielded %v for direction %v", p, d)
+		}
+		got4 = append(got4, p)
+	}
+	if want := []Position{{X: 1, Y: 0}, {X: 0, Y: 1}}; !slices.Equal(got4, want) {
+		t.Errorf("Neighbors4() = %v, want %v", got4, want)
+	}
+
+	var got8 []Position
+	for d, p := range grid.Neighbors8(Position{X: 1, Y: 1}) {
+		if p.Sub(Position{X: 1, Y: 1}) != (Position{}.Step(d, 1)) {
+			t.Errorf("Neighbors8 yielded %v for direction %v", p, d)
+		}
+		got8 = append(got8, p)
+	}
+	want8 := []Position{{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 1}, {X: 0, Y: 1}, {X: 0, Y: 0}}
+	if !slices.Equal(got8, want8) {
+		t.Errorf("Neighbors8() = %v, want %v", got8, want8)
+	}
+}
+
+func TestGridIterators(t *testing.T) {
+	grid := Grid[rune]{
+		{'1', '2', '3'},
+		{'4', '5', '6'},
+	}
+	var all []rune
+	for p, v := range grid.All() {
+		if grid.ItemAt(p) != v {
+			t.Errorf("All() yielded %c at %v, want %c", v, p, grid.ItemAt(p))
+		}
+		all = append(all, v)
+	}
+	if string(all) != "123456" {
+		t.Errorf("All() = %s, want 123456", string(all))
+	}
+	var col []rune
+	for _, v := range grid.Col(1) {
+		col = append(col, v)
+	}
+	if string(col) != "25" {
+		t.Errorf("Col(1) = %s, want 25", string(col))
+	}
+	var row []rune
+	for _, v := range grid.Row(1) {
+		row = append(row, v)
+	}
+	if string(row) != "456" {
+		t.Errorf("Row(1) = %s, want 456", string(row))
+	}
+}
+
+func TestParseAndMapGrid(t *testing.T) {
+	grid := Grid[rune]{{'1', '2'}, {'3', '4'}}
+	ints, err := ParseGrid(grid, ParseDigit)
+	if err != nil || !ints.Equal(Grid[int]{{1, 2}, {3, 4}}) {
+		t.Errorf("ParseGrid() = %v, %v", ints, err)
+	}
+	odd := MapGrid(ints, func(v int) bool { return v%2 == 1 })
+	if got := odd.String(); got != "#.\n#.\n" {
+		t.Errorf("MapGrid().String() = %q, want %q", got, "#.\n#.\n")
+	}
+
+	_, err = ParseGrid(Grid[rune]{{'1', '2'}, {'3', 'x'}}, ParseDigit)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Line != 2 || parseErr.Column != 2 || !errors.Is(err, ErrNotADigit) {
+		t.Errorf("ParseGrid() error = %v, want ErrNotADigit at line 2, column 2", err)
+	}
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"fmt"
 	"log"
 	"strconv"
 	"strings"
@@ -23,19 +24,48 @@ func Sign(x int) int {
 	return 0
 }
 
-func ConvertStringsToInts(s []string) []int {
+// A ParseError records where in the input a value failed to parse. Line and Column
+// are 1-based; a zero Line means the input wasn't read line by line.
+type ParseError struct {
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("column %d: %v", e.Column, e.Err)
+	}
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ConvertStringsToInts skips blank strings. On failure the ParseError's Column is the
+// 1-based index into s of the string that didn't parse.
+func ConvertStringsToInts(s []string) ([]int, error) {
 	var ret []int
-	for _, v := range s {
+	for idx, v := range s {
 		v = strings.TrimSpace(v)
 		if v == "" {
 			continue
 		}
 		i, err := strconv.Atoi(v)
 		if err != nil {
-			log.Fatal(err)
+			return nil, &ParseError{Column: idx + 1, Err: err}
 		}
 		ret = append(ret, i)
 	}
+	return ret, nil
+}
+
+func MustConvertStringsToInts(s []string) []int {
+	ret, err := ConvertStringsToInts(s)
+	if err != nil {
+		log.Fatal(err)
+	}
 	return ret
 }
 
@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestConvertStringsToInts(t *testing.T) {
+	got, err := ConvertStringsToInts([]string{"1", " 2", "", "-3 "})
+	if err != nil || !slices.Equal(got, []int{1, 2, -3}) {
+		t.Errorf("ConvertStringsToInts() = %v, %v, want [1 2 -3], nil", got, err)
+	}
+
+	_, err = ConvertStringsToInts([]string{"1", "", "x"})
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Column != 3 || !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("ConvertStringsToInts() error = %v, want a ParseError at column 3 wrapping strconv.ErrSyntax", err)
+	}
+}
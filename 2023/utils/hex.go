@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"fmt"
+	"iter"
+	"log"
+)
+
+// HexDirection names the six neighbors of a flat-topped hex in axial coordinates
+// (https://www.redblobgames.com/grids/hexagons/#coordinates-axial). Q grows to the
+// south-east and R grows to the south. The values go clockwise from HexNorth.
+type HexDirection int
+
+const (
+	HexNorth HexDirection = iota
+	HexNorthEast
+	HexSouthEast
+	HexSouth
+	HexSouthWest
+	HexNorthWest
+)
+
+var hexDirections = []HexDirection{HexNorth, HexNorthEast, HexSouthEast, HexSouth, HexSouthWest, HexNorthWest}
+
+func (d HexDirection) Valid() bool {
+	return d >= HexNorth && d <= HexNorthWest
+}
+
+// Rotate60 turns clockwise by 60 degrees for each step; negative steps turn counterclockwise.
+func (d HexDirection) Rotate60(steps int) HexDirection {
+	return HexDirection(((int(d)+steps)%6 + 6) % 6)
+}
+
+func (d HexDirection) Reverse() HexDirection {
+	return d.Rotate60(3)
+}
+
+func (d HexDirection) String() string {
+	switch d {
+	case HexNorth:
+		return "North"
+	case HexNorthEast:
+		return "NorthEast"
+	case HexSouthEast:
+		return "SouthEast"
+	case HexSouth:
+		return "South"
+	case HexSouthWest:
+		return "SouthWest"
+	case HexNorthWest:
+		return "NorthWest"
+	}
+	return fmt.Sprintf("HexDirection(%d)", int(d))
+}
+
+// returns DeltaQ, DeltaR
+func (d HexDirection) Delta() (int, int, error) {
+	switch d {
+	case HexNorth:
+		return 0, -1, nil
+	case HexNorthEast:
+		return 1, -1, nil
+	case HexSouthEast:
+		return 1, 0, nil
+	case HexSouth:
+		return 0, 1, nil
+	case HexSouthWest:
+		return -1, 1, nil
+	case HexNorthWest:
+		return -1, 0, nil
+	}
+	return 0, 0, fmt.Errorf("%w: %d", ErrInvalidDirection, int(d))
+}
+
+func (d HexDirection) MustDelta() (int, int) {
+	deltaQ, deltaR, err := d.Delta()
+	if err != nil {
+		log.Fatal(err)
+	}
+	return deltaQ, deltaR
+}
+
+type HexPosition struct {
+	Q int
+	R int
+}
+
+func (h HexPosition) String() string {
+	return fmt.Sprintf("(q=%d, r=%d)", h.Q, h.R)
+}
+
+// Position maps Q to X and R to Y, which lets a hex map be stored in a Grid or
+// SparseGrid and walked with Position.Step and Neighbors using HexDirections.
+func (h HexPosition) Position() Position {
+	return Position{X: h.Q, Y: h.R}
+}
+
+func HexPositionFrom(p Position) HexPosition {
+	return HexPosition{Q: p.X, R: p.Y}
+}
+
+func (h HexPosition) Add(other HexPosition) HexPosition {
+	return HexPosition{Q: h.Q + other.Q, R: h.R + other.R}
+}
+
+func (h HexPosition) Step(d HexDirection, n int) HexPosition {
+	deltaQ, deltaR := d.MustDelta()
+	return HexPosition{Q: h.Q + deltaQ*n, R: h.R + deltaR*n}
+}
+
+// Distance is the number of steps between two hexes.
+func (h HexPosition) Distance(other HexPosition) int {
+	deltaQ := h.Q - other.Q
+	deltaR := h.R - other.R
+	return (Abs(deltaQ) + Abs(deltaR) + Abs(deltaQ+deltaR)) / 2
+}
+
+func (h HexPosition) Neighbors() iter.Seq2[HexDirection, HexPosition] {
+	return func(yield func(HexDirection, HexPosition) bool) {
+		for _, d := range hexDirections {
+			if !yield(d, h.Step(d, 1)) {
+				return
+			}
+		}
+	}
+}
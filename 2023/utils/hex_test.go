@@ -0,0 +1,56 @@
+package utils
+
+import "testing"
+
+func TestHexDistance(t *testing.T) {
+	origin := HexPosition{}
+	tests := []struct {
+		p    HexPosition
+		want int
+	}{
+		{HexPosition{Q: 0, R: 0}, 0},
+		{HexPosition{Q: 1, R: -1}, 1},
+		{HexPosition{Q: 3, R: 0}, 3},
+		{HexPosition{Q: 2, R: 2}, 4},
+		{HexPosition{Q: -3, R: 1}, 3},
+		{origin.Step(HexNorth, 2).Step(HexSouthEast, 3), 3},
+	}
+	for _, tt := range tests {
+		if got := origin.Distance(tt.p); got != tt.want {
+			t.Errorf("Distance(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestHexNeighbors(t *testing.T) {
+	center := HexPosition{Q: 2, R: -1}
+	count := 0
+	for d, n := range center.Neighbors() {
+		count++
+		if center.Distance(n) != 1 {
+			t.Errorf("neighbor %v of %v is %d away", n, center, center.Distance(n))
+		}
+		if back := n.Step(d.Reverse(), 1); back != center {
+			t.Errorf("stepping %v back from %v = %v, want %v", d.Reverse(), n, back, center)
+		}
+		if next := d.Rotate60(1); d.Rotate60(-5) != next {
+			t.Errorf("%v.Rotate60(1) = %v, but Rotate60(-5) = %v", d, next, d.Rotate60(-5))
+		}
+	}
+	if count != 6 {
+		t.Errorf("Neighbors() yielded %d hexes, want 6", count)
+	}
+
+	grid := NewGrid(3, 3, '.')
+	got := 0
+	for d, p := range grid.NeighborsHex(Position{X: 0, Y: 0}) {
+		got++
+		if HexPositionFrom(p) != (HexPosition{}).Step(d, 1) {
+			t.Errorf("NeighborsHex yielded %v for %v", p, d)
+		}
+	}
+	// only South and SouthEast stay in bounds from the corner.
+	if got != 2 {
+		t.Errorf("NeighborsHex at the corner yielded %d positions, want 2", got)
+	}
+}
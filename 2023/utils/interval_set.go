@@ -0,0 +1,204 @@
+package utils
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+)
+
+// Interval is the half-open range of integers [Start, End). It's empty if End <= Start.
+type Interval struct {
+	Start int64
+	End   int64
+}
+
+func (iv Interval) Empty() bool {
+	return iv.End <= iv.Start
+}
+
+func (iv Interval) Len() int64 {
+	if iv.Empty() {
+		return 0
+	}
+	return iv.End - iv.Start
+}
+
+func (iv Interval) Contains(x int64) bool {
+	return iv.Start <= x && x < iv.End
+}
+
+func (iv Interval) String() string {
+	return fmt.Sprintf("[%d, %d)", iv.Start, iv.End)
+}
+
+// Interval converts to the equivalent half-open Interval, dropping the axis.
+func (xr *IntegerRangeWithAxis) Interval() Interval {
+	return Interval{Start: int64(xr.start), End: int64(xr.start + xr.length)}
+}
+
+// IntervalSet is a set of integers stored as sorted intervals that never overlap or
+// touch, so every set has exactly one representation. Operations return new sets and
+// never modify their inputs.
+type IntervalSet struct {
+	intervals []Interval
+}
+
+func NewIntervalSet(intervals ...Interval) IntervalSet {
+	sorted := make([]Interval, 0, len(intervals))
+	for _, iv := range intervals {
+		if !iv.Empty() {
+			sorted = append(sorted, iv)
+		}
+	}
+	slices.SortFunc(sorted, func(a, b Interval) int { return cmp.Compare(a.Start, b.Start) })
+	return IntervalSet{intervals: mergeSorted(sorted)}
+}
+
+// mergeSorted joins overlapping or touching intervals, which must already be sorted
+// by Start and non-empty. It reuses the input's storage.
+func mergeSorted(sorted []Interval) []Interval {
+	ret := sorted[:0]
+	for _, iv := range sorted {
+		if n := len(ret); n > 0 && iv.Start <= ret[n-1].End {
+			ret[n-1].End = max(ret[n-1].End, iv.End)
+		} else {
+			ret = append(ret, iv)
+		}
+	}
+	return ret
+}
+
+// All yields the set's intervals in increasing order.
+func (s IntervalSet) All() iter.Seq[Interval] {
+	return slices.Values(s.intervals)
+}
+
+// Values yields every integer in the set in increasing order, which can be a lot.
+func (s IntervalSet) Values() iter.Seq[int64] {
+	return func(yield func(int64) bool) {
+		for _, iv := range s.intervals {
+			for x := iv.Start; x < iv.End; x++ {
+				if !yield(x) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s IntervalSet) NumIntervals() int {
+	return len(s.intervals)
+}
+
+func (s IntervalSet) IsEmpty() bool {
+	return len(s.intervals) == 0
+}
+
+// Len returns how many integers are in the set.
+func (s IntervalSet) Len() int64 {
+	var ret int64
+	for _, iv := range s.intervals {
+		ret += iv.Len()
+	}
+	return ret
+}
+
+// Min returns the smallest integer in the set, or false if it's empty.
+func (s IntervalSet) Min() (int64, bool) {
+	if len(s.intervals) == 0 {
+		return 0, false
+	}
+	return s.intervals[0].Start, true
+}
+
+// Max returns the largest integer in the set, or false if it's empty.
+func (s IntervalSet) Max() (int64, bool) {
+	if len(s.intervals) == 0 {
+		return 0, false
+	}
+	return s.intervals[len(s.intervals)-1].End - 1, true
+}
+
+// Contains binary searches for the interval that would hold x.
+func (s IntervalSet) Contains(x int64) bool {
+	// the first interval ending after x is the only one that can contain it.
+	i, _ := slices.BinarySearchFunc(s.intervals, x, func(iv Interval, x int64) int {
+		if iv.End <= x {
+			return -1
+		}
+		return 1
+	})
+	return i < len(s.intervals) && s.intervals[i].Contains(x)
+}
+
+func (s IntervalSet) Union(other IntervalSet) IntervalSet {
+	merged := make([]Interval, 0, len(s.intervals)+len(other.intervals))
+	i, j := 0, 0
+	for i < len(s.intervals) || j < len(other.intervals) {
+		if j == len(other.intervals) || (i < len(s.intervals) && s.intervals[i].Start <= other.intervals[j].Start) {
+			merged = append(merged, s.intervals[i])
+			i++
+		} else {
+			merged = append(merged, other.intervals[j])
+			j++
+		}
+	}
+	return IntervalSet{intervals: mergeSorted(merged)}
+}
+
+func (s IntervalSet) Intersection(other IntervalSet) IntervalSet {
+	ret := make([]Interval, 0)
+	i, j := 0, 0
+	for i < len(s.intervals) && j < len(other.intervals) {
+		a, b := s.intervals[i], other.intervals[j]
+		if overlap := (Interval{Start: max(a.Start, b.Start), End: min(a.End, b.End)}); !overlap.Empty() {
+			ret = append(ret, overlap)
+		}
+		// whichever ends first can't overlap anything else in the other set.
+		if a.End < b.End {
+			i++
+		} else {
+			j++
+		}
+	}
+	return IntervalSet{intervals: ret}
+}
+
+// Complement returns everything within bounds that isn't in the set.
+func (s IntervalSet) Complement(bounds Interval) IntervalSet {
+	ret := make([]Interval, 0)
+	next := bounds.Start
+	for _, iv := range s.intervals {
+		if gap := (Interval{Start: next, End: min(iv.Start, bounds.End)}); !gap.Empty() {
+			ret = append(ret, gap)
+		}
+		next = max(next, iv.End)
+	}
+	if gap := (Interval{Start: next, End: bounds.End}); !gap.Empty() {
+		ret = append(ret, gap)
+	}
+	return IntervalSet{intervals: ret}
+}
+
+// Difference returns the integers in s that aren't in other.
+func (s IntervalSet) Difference(other IntervalSet) IntervalSet {
+	if len(s.intervals) == 0 {
+		return s
+	}
+	hull := Interval{Start: s.intervals[0].Start, End: s.intervals[len(s.intervals)-1].End}
+	return s.Intersection(other.Complement(hull))
+}
+
+func (s IntervalSet) Equal(other IntervalSet) bool {
+	return slices.Equal(s.intervals, other.intervals)
+}
+
+func (s IntervalSet) String() string {
+	parts := make([]string, len(s.intervals))
+	for i, iv := range s.intervals {
+		parts[i] = iv.String()
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func iv(start, end int64) Interval {
+	return Interval{Start: start, End: end}
+}
+
+func TestNewIntervalSet(t *testing.T) {
+	s := NewIntervalSet(iv(10, 20), iv(0, 5), iv(5, 7), iv(15, 25), iv(30, 30), iv(40, 35))
+	if got, want := s.String(), "{[0, 7), [10, 25)}"; got != want {
+		t.Errorf("NewIntervalSet() = %v, want %v", got, want)
+	}
+	if s.Len() != 22 || s.NumIntervals() != 2 {
+		t.Errorf("Len() = %v, NumIntervals() = %v, want 22, 2", s.Len(), s.NumIntervals())
+	}
+	if lo, _ := s.Min(); lo != 0 {
+		t.Errorf("Min() = %v, want 0", lo)
+	}
+	if hi, _ := s.Max(); hi != 24 {
+		t.Errorf("Max() = %v, want 24", hi)
+	}
+	if _, ok := NewIntervalSet().Min(); ok {
+		t.Error("Min() of an empty set returned ok")
+	}
+	for x, want := range map[int64]bool{-1: false, 0: true, 6: true, 7: false, 9: false, 10: true, 24: true, 25: false} {
+		if got := s.Contains(x); got != want {
+			t.Errorf("Contains(%d) = %v, want %v", x, got, want)
+		}
+	}
+}
+
+func TestIntervalSetOperations(t *testing.T) {
+	a := NewIntervalSet(iv(0, 10), iv(20, 30))
+	b := NewIntervalSet(iv(5, 25), iv(28, 40))
+	tests := []struct {
+		name string
+		got  IntervalSet
+		want string
+	}{
+		{"union", a.Union(b), "{[0, 40)}"},
+		{"intersection", a.Intersection(b), "{[5, 10), [20, 25), [28, 30)}"},
+		{"a - b", a.Difference(b), "{[0, 5), [25, 28)}"},
+		{"b - a", b.Difference(a), "{[10, 20), [30, 40)}"},
+		{"complement", a.Complement(iv(-5, 25)), "{[-5, 0), [10, 20)}"},
+		{"complement of empty", NewIntervalSet().Complement(iv(1, 3)), "{[1, 3)}"},
+		{"empty intersection", a.Intersection(NewIntervalSet(iv(10, 20))), "{}"},
+	}
+	for _, tt := range tests {
+		if tt.got.String() != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if got := slices.Collect(NewIntervalSet(iv(3, 5), iv(8, 9)).Values()); !slices.Equal(got, []int64{3, 4, 8}) {
+		t.Errorf("Values() = %v", got)
+	}
+}
+
+// compare against plain maps of integers on lots of small random sets.
+func TestIntervalSetRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomSet := func() (IntervalSet, map[int64]bool) {
+		intervals := make([]Interval, 0)
+		members := make(map[int64]bool)
+		for range r.Intn(5) {
+			start := int64(r.Intn(50))
+			end := start + int64(r.Intn(10))
+			intervals = append(intervals, iv(start, end))
+			for x := start; x < end; x++ {
+				members[x] = true
+			}
+		}
+		return NewIntervalSet(intervals...), members
+	}
+	for range 500 {
+		a, aMembers := randomSet()
+		b, bMembers := randomSet()
+		union, intersection, difference := a.Union(b), a.Intersection(b), a.Difference(b)
+		complement := a.Complement(iv(10, 40))
+		for x := int64(-1); x < 62; x++ {
+			if union.Contains(x) != (aMembers[x] || bMembers[x]) ||
+				intersection.Contains(x) != (aMembers[x] && bMembers[x]) ||
+				difference.Contains(x) != (aMembers[x] && !bMembers[x]) ||
+				complement.Contains(x) != (x >= 10 && x < 40 && !aMembers[x]) {
+				t.Fatalf("wrong answer for %d with a=%v b=%v", x, a, b)
+			}
+		}
+		// results must stay normalized.
+		for _, s := range []IntervalSet{union, intersection, difference, complement} {
+			if !s.Equal(NewIntervalSet(slices.Collect(s.All())...)) {
+				t.Fatalf("%v isn't normalized", s)
+			}
+			for i := 1; i < s.NumIntervals(); i++ {
+				if s.intervals[i-1].End >= s.intervals[i].Start {
+					t.Fatalf("%v has touching intervals", s)
+				}
+			}
+		}
+	}
+}
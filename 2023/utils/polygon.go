@@ -0,0 +1,205 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"math"
+)
+
+// Polygon is a closed shape with integer vertices, with an edge from each vertex to the
+// next and from the last back to the first. Since Y grows downward (North is -Y), "clockwise"
+// here means clockwise as drawn on screen.
+type Polygon struct {
+	Vertices []Position
+}
+
+// NewPolygon makes a polygon from its vertices in order. A path that ends where it
+// started, and vertices repeated back to back, are fine; the duplicates are dropped.
+func NewPolygon(vertices []Position) Polygon {
+	ret := make([]Position, 0, len(vertices))
+	for _, v := range vertices {
+		if len(ret) == 0 || ret[len(ret)-1] != v {
+			ret = append(ret, v)
+		}
+	}
+	for len(ret) > 1 && ret[len(ret)-1] == ret[0] {
+		ret = ret[:len(ret)-1]
+	}
+	return Polygon{Vertices: ret}
+}
+
+// PolygonMove is one instruction for tracing a polygon: go Length steps in Direction.
+type PolygonMove struct {
+	Direction Direction
+	Length    int
+}
+
+// NewPolygonFromMoves traces a polygon by following moves from start. Only the corners
+// are kept, so long moves cost nothing extra. The moves have to end back at start.
+func NewPolygonFromMoves(start Position, moves []PolygonMove) (Polygon, error) {
+	vertices := []Position{start}
+	curr := start
+	for _, move := range moves {
+		curr = curr.Step(move.Direction, move.Length)
+		vertices = append(vertices, curr)
+	}
+	if curr != start {
+		return Polygon{}, fmt.Errorf("moves end at %v instead of back at %v", curr, start)
+	}
+	return NewPolygon(vertices), nil
+}
+
+func MustNewPolygonFromMoves(start Position, moves []PolygonMove) Polygon {
+	p, err := NewPolygonFromMoves(start, moves)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return p
+}
+
+// edges yields each edge as a pair of vertices, wrapping around at the end.
+func (p Polygon) edges(yield func(Position, Position) bool) {
+	for i, a := range p.Vertices {
+		if !yield(a, p.Vertices[(i+1)%len(p.Vertices)]) {
+			return
+		}
+	}
+}
+
+// DoubleSignedArea is twice the area from the shoelace formula, which is always an
+// integer. It's positive when the polygon goes clockwise.
+func (p Polygon) DoubleSignedArea() int {
+	// https://en.wikipedia.org/wiki/Shoelace_theorem
+	// https://math.stackexchange.com/questions/1218/how-to-calculate-the-area-of-a-polygon-given-its-vertices
+	area := 0
+	for a, b := range p.edges {
+		area += a.X*b.Y - b.X*a.Y
+	}
+	return area
+}
+
+// Area is half of DoubleSignedArea, ignoring its sign, so it can end in .5.
+func (p Polygon) Area() float64 {
+	return float64(Abs(p.DoubleSignedArea())) / 2
+}
+
+type Orientation int
+
+const (
+	// Degenerate polygons enclose no area, like a line walked there and back.
+	Degenerate Orientation = iota
+	Clockwise
+	CounterClockwise
+)
+
+func (o Orientation) String() string {
+	switch o {
+	case Degenerate:
+		return "Degenerate"
+	case Clockwise:
+		return "Clockwise"
+	case CounterClockwise:
+		return "CounterClockwise"
+	}
+	return fmt.Sprintf("Orientation(%d)", int(o))
+}
+
+func (p Polygon) Orientation() Orientation {
+	switch area := p.DoubleSignedArea(); {
+	case area > 0:
+		return Clockwise
+	case area < 0:
+		return CounterClockwise
+	}
+	return Degenerate
+}
+
+// Perimeter is the total length of the edges.
+func (p Polygon) Perimeter() float64 {
+	ret := 0.0
+	for a, b := range p.edges {
+		ret += math.Hypot(float64(b.X-a.X), float64(b.Y-a.Y))
+	}
+	return ret
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// LatticeBoundaryPoints counts the integer points on the edges, vertices included.
+func (p Polygon) LatticeBoundaryPoints() int {
+	ret := 0
+	for a, b := range p.edges {
+		// an edge from a to b passes through gcd(dx, dy) points, counting b but not a.
+		ret += gcd(Abs(b.X-a.X), Abs(b.Y-a.Y))
+	}
+	return ret
+}
+
+// LatticeInteriorPoints counts the integer points strictly inside the polygon without
+// visiting them, using Pick's theorem: A = I + B/2 - 1. The polygon mustn't cross itself.
+func (p Polygon) LatticeInteriorPoints() int {
+	return (Abs(p.DoubleSignedArea()) - p.LatticeBoundaryPoints() + 2) / 2
+}
+
+// cross is the z component of (b - a) x (c - a): positive when c is clockwise from b as
+// seen from a, on screen.
+func cross(a, b, c Position) int {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+// OnBoundary checks whether pos lies on one of the edges.
+func (p Polygon) OnBoundary(pos Position) bool {
+	for a, b := range p.edges {
+		if cross(a, b, pos) == 0 &&
+			min(a.X, b.X) <= pos.X && pos.X <= max(a.X, b.X) &&
+			min(a.Y, b.Y) <= pos.Y && pos.Y <= max(a.Y, b.Y) {
+			return true
+		}
+	}
+	return false
+}
+
+// WindingNumber counts how many times the polygon goes around pos, positive for
+// clockwise. It's meaningless for points on the boundary.
+func (p Polygon) WindingNumber(pos Position) int {
+	ret := 0
+	for a, b := range p.edges {
+		// count edges crossing the horizontal line through pos to its right, downward
+		// crossings one way and upward ones the other.
+		if a.Y <= pos.Y && b.Y > pos.Y && cross(a, b, pos) > 0 {
+			ret++
+		} else if a.Y > pos.Y && b.Y <= pos.Y && cross(a, b, pos) < 0 {
+			ret--
+		}
+	}
+	return ret
+}
+
+type FillRule int
+
+const (
+	// EvenOdd counts a point as inside if a ray from it crosses the boundary an odd
+	// number of times.
+	EvenOdd FillRule = iota
+	// NonZero counts a point as inside if the polygon winds around it at all.
+	NonZero
+)
+
+// ContainsPoint checks whether pos is strictly inside the polygon. Points on the
+// boundary aren't; use OnBoundary for those. The rules only differ for polygons that
+// cross themselves.
+func (p Polygon) ContainsPoint(pos Position, rule FillRule) bool {
+	if p.OnBoundary(pos) {
+		return false
+	}
+	winding := p.WindingNumber(pos)
+	if rule == EvenOdd {
+		return winding%2 != 0
+	}
+	return winding != 0
+}
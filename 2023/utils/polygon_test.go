@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPolygonSquare(t *testing.T) {
+	// a 4x4 square drawn clockwise, closed by repeating the first vertex.
+	square := NewPolygon([]Position{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}, {X: 0, Y: 0}})
+	if len(square.Vertices) != 4 {
+		t.Errorf("NewPolygon() kept %v", square.Vertices)
+	}
+	if got := square.Area(); got != 16 {
+		t.Errorf("Area() = %v, want 16", got)
+	}
+	if got := square.Perimeter(); got != 16 {
+		t.Errorf("Perimeter() = %v, want 16", got)
+	}
+	if got := square.LatticeBoundaryPoints(); got != 16 {
+		t.Errorf("LatticeBoundaryPoints() = %v, want 16", got)
+	}
+	if got := square.LatticeInteriorPoints(); got != 9 {
+		t.Errorf("LatticeInteriorPoints() = %v, want 9", got)
+	}
+	if got := square.Orientation(); got != Clockwise {
+		t.Errorf("Orientation() = %v, want Clockwise", got)
+	}
+	reversed := NewPolygon([]Position{{X: 0, Y: 0}, {X: 0, Y: 4}, {X: 4, Y: 4}, {X: 4, Y: 0}})
+	if got := reversed.Orientation(); got != CounterClockwise {
+		t.Errorf("Orientation() = %v, want CounterClockwise", got)
+	}
+	if got := reversed.WindingNumber(Position{X: 2, Y: 2}); got != -1 {
+		t.Errorf("WindingNumber() = %v, want -1", got)
+	}
+	if got := NewPolygon([]Position{{X: 0, Y: 0}, {X: 3, Y: 0}}).Orientation(); got != Degenerate {
+		t.Errorf("Orientation() = %v, want Degenerate", got)
+	}
+}
+
+func TestPolygonTriangle(t *testing.T) {
+	triangle := NewPolygon([]Position{{X: 0, Y: 0}, {X: 3, Y: 0}, {X: 0, Y: 3}})
+	if got := triangle.Area(); got != 4.5 {
+		t.Errorf("Area() = %v, want 4.5", got)
+	}
+	if got, want := triangle.Perimeter(), 6+3*math.Sqrt2; math.Abs(got-want) > 1e-9 {
+		t.Errorf("Perimeter() = %v, want %v", got, want)
+	}
+	// (0,0) (1,0) (2,0) (3,0) (2,1) (1,2) (0,3) (0,2) (0,1), and just (1,1) inside.
+	if got := triangle.LatticeBoundaryPoints(); got != 9 {
+		t.Errorf("LatticeBoundaryPoints() = %v, want 9", got)
+	}
+	if got := triangle.LatticeInteriorPoints(); got != 1 {
+		t.Errorf("LatticeInteriorPoints() = %v, want 1", got)
+	}
+}
+
+func TestPolygonFromMoves(t *testing.T) {
+	// the example trench from 2023 day 18.
+	moves := []PolygonMove{
+		{East, 6}, {South, 5}, {West, 2}, {South, 2}, {East, 2}, {South, 2}, {West, 5},
+		{North, 2}, {West, 1}, {North, 2}, {East, 2}, {North, 3}, {West, 2}, {North, 2},
+	}
+	p := MustNewPolygonFromMoves(Position{}, moves)
+	if got := p.LatticeInteriorPoints() + p.LatticeBoundaryPoints(); got != 62 {
+		t.Errorf("interior + boundary = %v, want 62", got)
+	}
+	if _, err := NewPolygonFromMoves(Position{}, moves[:3]); err == nil {
+		t.Error("NewPolygonFromMoves() accepted moves that don't close")
+	}
+}
+
+// check ContainsPoint against counting with Pick's theorem on the day 18 example, and
+// the fill rules against each other on a self-crossing star.
+func TestPolygonContainsPoint(t *testing.T) {
+	p := MustNewPolygonFromMoves(Position{}, []PolygonMove{
+		{East, 6}, {South, 5}, {West, 2}, {South, 2}, {East, 2}, {South, 2}, {West, 5},
+		{North, 2}, {West, 1}, {North, 2}, {East, 2}, {North, 3}, {West, 2}, {North, 2},
+	})
+	inside, boundary := 0, 0
+	for y := -1; y <= 10; y++ {
+		for x := -1; x <= 7; x++ {
+			pos := Position{X: x, Y: y}
+			if p.OnBoundary(pos) {
+				boundary++
+			}
+			if p.ContainsPoint(pos, EvenOdd) {
+				inside++
+				if !p.ContainsPoint(pos, NonZero) {
+					t.Errorf("ContainsPoint(%v) differs between rules", pos)
+				}
+			}
+		}
+	}
+	if inside != p.LatticeInteriorPoints() || boundary != p.LatticeBoundaryPoints() {
+		t.Errorf("counted %d inside and %d on the boundary, want %d and %d", inside, boundary, p.LatticeInteriorPoints(), p.LatticeBoundaryPoints())
+	}
+
+	// a pentagram: the middle is wound around twice.
+	star := NewPolygon([]Position{{X: 0, Y: -10}, {X: 6, Y: 8}, {X: -9, Y: -3}, {X: 9, Y: -3}, {X: -6, Y: 8}})
+	center := Position{X: 0, Y: 0}
+	if star.ContainsPoint(center, EvenOdd) || !star.ContainsPoint(center, NonZero) {
+		t.Errorf("center of a star: even-odd %v, nonzero %v", star.ContainsPoint(center, EvenOdd), star.ContainsPoint(center, NonZero))
+	}
+	if w := star.WindingNumber(center); w != 2 && w != -2 {
+		t.Errorf("WindingNumber() = %v, want ±2", w)
+	}
+	tip := Position{X: 0, Y: -7}
+	if !star.ContainsPoint(tip, EvenOdd) || !star.ContainsPoint(tip, NonZero) {
+		t.Errorf("a point of the star isn't inside")
+	}
+}
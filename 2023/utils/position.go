@@ -58,6 +58,25 @@ func (p *Position) FollowDirection(d Direction, maxXValue int, maxYValue int) (b
 	return false, Position{}
 }
 
+// Add, Sub, Scale and Step don't stop at zero or any maximum, so they work for puzzles
+// whose coordinates go negative. They take values rather than pointers so they chain.
+func (p Position) Add(other Position) Position {
+	return Position{X: p.X + other.X, Y: p.Y + other.Y}
+}
+
+func (p Position) Sub(other Position) Position {
+	return Position{X: p.X - other.X, Y: p.Y - other.Y}
+}
+
+func (p Position) Scale(k int) Position {
+	return Position{X: p.X * k, Y: p.Y * k}
+}
+
+func (p Position) Step(d Stepper, n int) Position {
+	deltaX, deltaY := d.MustDelta()
+	return Position{X: p.X + deltaX*n, Y: p.Y + deltaY*n}
+}
+
 func (p *Position) ManhattanDistance(other Position) int {
 	return Abs(p.X-other.X) + Abs(p.Y-other.Y)
 }
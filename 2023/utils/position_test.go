@@ -0,0 +1,22 @@
+package utils
+
+import "testing"
+
+func TestPositionArithmetic(t *testing.T) {
+	p := Position{X: 1, Y: 2}
+	if got := p.Add(Position{X: -3, Y: 4}); got != (Position{X: -2, Y: 6}) {
+		t.Errorf("Add() = %v, want (-2, 6)", got)
+	}
+	if got := p.Sub(Position{X: 3, Y: 4}); got != (Position{X: -2, Y: -2}) {
+		t.Errorf("Sub() = %v, want (-2, -2)", got)
+	}
+	if got := p.Scale(-2); got != (Position{X: -2, Y: -4}) {
+		t.Errorf("Scale() = %v, want (-2, -4)", got)
+	}
+	if got := p.Step(North, 5); got != (Position{X: 1, Y: -3}) {
+		t.Errorf("Step(North, 5) = %v, want (1, -3)", got)
+	}
+	if got := p.Step(West, 2).Step(South, 1); got != (Position{X: -1, Y: 3}) {
+		t.Errorf("Step(West, 2).Step(South, 1) = %v, want (-1, 3)", got)
+	}
+}
@@ -1,4 +1,4 @@
-// This is all shamelessly stolen from https://pkg.go.dev/container/heap
+// This started out as the PriorityQueue example from https://pkg.go.dev/container/heap
 // BSD-Licensed!
 package utils
 
@@ -6,59 +6,103 @@ import (
 	"container/heap"
 )
 
-// An Item is something we manage in a priority queue.
-type Item[T comparable] struct {
-	value    T   // The value of the item; arbitrary.
-	priority int // The priority of the item in the queue.
-	// The index is needed by update and is maintained by the heap.Interface methods.
-	index int // The index of the item in the heap.
+type heapItem[T comparable] struct {
+	value    T
+	priority int
 }
 
-// A PriorityQueue implements heap.Interface and holds Items.
-type PriorityQueue[T comparable] []*Item[T]
+// heapItems implements heap.Interface, keeping index up to date as items move so
+// that any value can be found in O(1).
+type heapItems[T comparable] struct {
+	items []heapItem[T]
+	index map[T]int
+}
 
-func (pq PriorityQueue[T]) Len() int { return len(pq) }
+func (h *heapItems[T]) Len() int { return len(h.items) }
 
-func (pq PriorityQueue[T]) Less(i, j int) bool {
+func (h *heapItems[T]) Less(i, j int) bool {
 	// adjusted from the google example to really use less-than.
-	return pq[i].priority < pq[j].priority
+	return h.items[i].priority < h.items[j].priority
 }
 
-func (pq PriorityQueue[T]) Swap(i, j int) {
-	pq[i], pq[j] = pq[j], pq[i]
-	pq[i].index = i
-	pq[j].index = j
+func (h *heapItems[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.index[h.items[i].value] = i
+	h.index[h.items[j].value] = j
 }
 
-func (pq *PriorityQueue[T]) Push(x any) {
-	n := len(*pq)
-	item := x.(*Item[T])
-	item.index = n
-	*pq = append(*pq, item)
+func (h *heapItems[T]) Push(x any) {
+	item := x.(heapItem[T])
+	h.index[item.value] = len(h.items)
+	h.items = append(h.items, item)
 }
 
-func (pq *PriorityQueue[T]) Pop() any {
-	old := *pq
-	n := len(old)
-	item := old[n-1]
-	old[n-1] = nil  // don't stop the GC from reclaiming the item eventually
-	item.index = -1 // for safety
-	*pq = old[0 : n-1]
+func (h *heapItems[T]) Pop() any {
+	n := len(h.items)
+	item := h.items[n-1]
+	h.items = h.items[0 : n-1]
+	delete(h.index, item.value)
 	return item
 }
 
-// update modifies the priority and value of an Item in the queue.
-func (pq *PriorityQueue[T]) update(item *Item[T], value T, priority int) {
-	item.value = value
-	item.priority = priority
-	heap.Fix(pq, item.index)
+// An IndexedHeap is a min-priority queue that also knows where each value is, so
+// Contains, Priority and DecreaseKey don't have to scan the queue. Each value can
+// be queued at most once.
+type IndexedHeap[T comparable] struct {
+	h heapItems[T]
+}
+
+func NewIndexedHeap[T comparable]() *IndexedHeap[T] {
+	return &IndexedHeap[T]{h: heapItems[T]{index: make(map[T]int)}}
+}
+
+func (q *IndexedHeap[T]) Len() int {
+	return q.h.Len()
+}
+
+func (q *IndexedHeap[T]) Contains(value T) bool {
+	_, ok := q.h.index[value]
+	return ok
+}
+
+// Priority returns the priority value is queued with, or false if it isn't queued.
+func (q *IndexedHeap[T]) Priority(value T) (int, bool) {
+	idx, ok := q.h.index[value]
+	if !ok {
+		return 0, false
+	}
+	return q.h.items[idx].priority, true
+}
+
+// Push adds value to the queue. If value is already queued, its priority is replaced.
+func (q *IndexedHeap[T]) Push(value T, priority int) {
+	if idx, ok := q.h.index[value]; ok {
+		q.h.items[idx].priority = priority
+		heap.Fix(&q.h, idx)
+		return
+	}
+	heap.Push(&q.h, heapItem[T]{value: value, priority: priority})
+}
+
+// PopMin removes and returns the value with the lowest priority. It returns false if
+// the queue is empty.
+func (q *IndexedHeap[T]) PopMin() (T, int, bool) {
+	if q.h.Len() == 0 {
+		var zero T
+		return zero, 0, false
+	}
+	item := heap.Pop(&q.h).(heapItem[T])
+	return item.value, item.priority, true
 }
 
-func (pq *PriorityQueue[T]) Get(value T) *Item[T] {
-	for _, i := range *pq {
-		if i.value == value {
-			return i
-		}
+// DecreaseKey lowers the priority of a queued value. It returns false, leaving the
+// queue alone, if value isn't queued or priority is higher than its current one.
+func (q *IndexedHeap[T]) DecreaseKey(value T, priority int) bool {
+	idx, ok := q.h.index[value]
+	if !ok || priority > q.h.items[idx].priority {
+		return false
 	}
-	return nil
+	q.h.items[idx].priority = priority
+	heap.Fix(&q.h, idx)
+	return true
 }
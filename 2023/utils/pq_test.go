@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIndexedHeap(t *testing.T) {
+	q := NewIndexedHeap[string]()
+	q.Push("c", 3)
+	q.Push("a", 5)
+	q.Push("b", 2)
+	q.Push("d", 4)
+
+	if !q.Contains("a") || q.Contains("z") {
+		t.Errorf("Contains() wrong: a=%v z=%v", q.Contains("a"), q.Contains("z"))
+	}
+	if !q.DecreaseKey("a", 1) {
+		t.Error("DecreaseKey(a, 1) = false, want true")
+	}
+	if q.DecreaseKey("d", 10) {
+		t.Error("DecreaseKey(d, 10) = true, want false")
+	}
+	if q.DecreaseKey("z", 0) {
+		t.Error("DecreaseKey(z, 0) = true, want false")
+	}
+	if p, ok := q.Priority("d"); !ok || p != 4 {
+		t.Errorf("Priority(d) = %v, %v, want 4, true", p, ok)
+	}
+
+	var got []string
+	for q.Len() > 0 {
+		value, _, _ := q.PopMin()
+		if q.Contains(value) {
+			t.Errorf("Contains(%v) = true after popping it", value)
+		}
+		got = append(got, value)
+	}
+	if want := []string{"a", "b", "c", "d"}; !slices.Equal(got, want) {
+		t.Errorf("PopMin() order = %v, want %v", got, want)
+	}
+	if _, _, ok := q.PopMin(); ok {
+		t.Error("PopMin() on an empty heap returned ok")
+	}
+}
+
+func TestIndexedHeapManyValues(t *testing.T) {
+	q := NewIndexedHeap[Position]()
+	for i := range 200 {
+		q.Push(Position{X: i}, (i*37)%101)
+	}
+	for i := range 200 {
+		if i%3 == 0 {
+			q.DecreaseKey(Position{X: i}, -i)
+		}
+	}
+	last := -1 << 31
+	for q.Len() > 0 {
+		_, priority, _ := q.PopMin()
+		if priority < last {
+			t.Fatalf("PopMin() returned priority %d after %d", priority, last)
+		}
+		last = priority
+	}
+}
+
+func makeBenchmarkGridGraph(size int) Graph[Position] {
+	graph := make(Graph[Position])
+	grid := NewGrid(size, size, 0)
+	for pos := range grid.All() {
+		grid.Set(pos, 1+(pos.X*7+pos.Y*13)%9)
+	}
+	for pos := range grid.All() {
+		for _, next := range grid.Neighbors4(pos) {
+			graph.AddEdge(pos, next, grid.ItemAt(next))
+		}
+	}
+	return graph
+}
+
+func BenchmarkFindDistanceAndPath(b *testing.B) {
+	graph := makeBenchmarkGridGraph(100)
+	froms := []Position{{X: 0, Y: 0}}
+	ends := []Position{{X: 99, Y: 99}}
+	b.ResetTimer()
+	for range b.N {
+		graph.MustFindDistanceAndPath(froms, ends)
+	}
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"cmp"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+)
+
+// A PartFunc solves one half of a puzzle given the path to an input file.
+type PartFunc func(fname string) int
+
+type Puzzle struct {
+	Year  int
+	Day   int
+	Part1 PartFunc
+	Part2 PartFunc
+	// Dir is the directory holding the day's source and input files, so inputs can
+	// be found no matter which directory the runner is started from. It's the source
+	// path recorded when the binary was built, so it's only right on that machine and
+	// without -trimpath; otherwise inputs have to be found from the working directory.
+	Dir string
+}
+
+type puzzleKey struct {
+	year int
+	day  int
+}
+
+var puzzles = make(map[puzzleKey]*Puzzle)
+
+// RegisterPuzzle is meant to be called from a day's init function.
+func RegisterPuzzle(year int, day int, part1 PartFunc, part2 PartFunc) {
+	key := puzzleKey{year: year, day: day}
+	if _, ok := puzzles[key]; ok {
+		panic("puzzle registered twice")
+	}
+	dir := ""
+	if _, file, _, ok := runtime.Caller(1); ok {
+		dir = filepath.Dir(file)
+	}
+	puzzles[key] = &Puzzle{Year: year, Day: day, Part1: part1, Part2: part2, Dir: dir}
+}
+
+func LookupPuzzle(year int, day int) (*Puzzle, bool) {
+	p, ok := puzzles[puzzleKey{year: year, day: day}]
+	return p, ok
+}
+
+// AllPuzzles returns every registered puzzle ordered by year and then day.
+func AllPuzzles() []*Puzzle {
+	ret := make([]*Puzzle, 0, len(puzzles))
+	for _, p := range puzzles {
+		ret = append(ret, p)
+	}
+	slices.SortFunc(ret, func(a, b *Puzzle) int {
+		return cmp.Or(cmp.Compare(a.Year, b.Year), cmp.Compare(a.Day, b.Day))
+	})
+	return ret
+}
+
+func (p *Puzzle) Part(part int) (PartFunc, bool) {
+	switch part {
+	case 1:
+		return p.Part1, p.Part1 != nil
+	case 2:
+		return p.Part2, p.Part2 != nil
+	}
+	return nil, false
+}
+
+// ResolveInput returns fname unchanged if it exists relative to the working directory,
+// and otherwise looks for it in the puzzle's own directory. If it's in neither, fname
+// comes back unchanged, so a binary whose Dir is stale still works when run from the
+// day's directory or given a full path.
+func (p *Puzzle) ResolveInput(fname string) string {
+	if filepath.IsAbs(fname) || p.Dir == "" {
+		return fname
+	}
+	if _, err := os.Stat(fname); err == nil {
+		return fname
+	}
+	inDir := filepath.Join(p.Dir, fname)
+	if _, err := os.Stat(inDir); err == nil {
+		return inDir
+	}
+	return fname
+}
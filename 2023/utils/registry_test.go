@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRegisterPuzzle(t *testing.T) {
+	part1 := func(fname string) int { return 1 }
+	RegisterPuzzle(1, 1, part1, nil)
+	defer delete(puzzles, puzzleKey{year: 1, day: 1})
+
+	p, ok := LookupPuzzle(1, 1)
+	if !ok {
+		t.Fatal("LookupPuzzle(1, 1) found nothing after registering it")
+	}
+	if f, ok := p.Part(1); !ok || f("") != 1 {
+		t.Errorf("Part(1) = %v, want the registered part1", ok)
+	}
+	if _, ok := p.Part(2); ok {
+		t.Error("Part(2) should not be found when part2 is nil")
+	}
+	if _, ok := LookupPuzzle(1, 2); ok {
+		t.Error("LookupPuzzle(1, 2) should not find an unregistered day")
+	}
+
+	// Dir is the directory of the caller, so this file can be found from anywhere.
+	want := filepath.Join(p.Dir, "registry_test.go")
+	if got := p.ResolveInput("registry_test.go"); got != "registry_test.go" && got != want {
+		t.Errorf("ResolveInput() = %v, want %v", got, want)
+	}
+	if got := p.ResolveInput("does-not-exist.txt"); got != "does-not-exist.txt" {
+		t.Errorf("ResolveInput() = %v, want it unchanged", got)
+	}
+}
@@ -0,0 +1,212 @@
+package utils
+
+import (
+	"cmp"
+	"iter"
+	"maps"
+	"slices"
+)
+
+// UndirectedGraph stores each node's neighbors as a set, so checking for an edge is
+// O(1). Every edge is stored in both directions.
+type UndirectedGraph[T comparable] map[T]map[T]bool
+
+// NewUndirectedGraph builds a graph from a list of edges, each a pair of nodes.
+func NewUndirectedGraph[T comparable](edges ...[2]T) UndirectedGraph[T] {
+	graph := make(UndirectedGraph[T])
+	for _, edge := range edges {
+		graph.AddEdge(edge[0], edge[1])
+	}
+	return graph
+}
+
+// Undirected drops the direction and cost of every edge. Self loops are dropped too.
+func (graph *Graph[T]) Undirected() UndirectedGraph[T] {
+	ret := make(UndirectedGraph[T])
+	for _, node := range graph.Nodes() {
+		ret.AddNode(node)
+	}
+	for _, edge := range graph.Edges() {
+		ret.AddEdge(edge.From, edge.To)
+	}
+	return ret
+}
+
+// Directed converts back to a Graph, with each edge both ways at the given cost.
+func (graph *UndirectedGraph[T]) Directed(cost int) Graph[T] {
+	ret := make(Graph[T])
+	for node, neighbors := range *graph {
+		ret[node] = make([]Neighbor[T], 0, len(neighbors))
+		for neighbor := range neighbors {
+			ret.AddEdge(node, neighbor, cost)
+		}
+	}
+	return ret
+}
+
+func (graph *UndirectedGraph[T]) AddNode(node T) {
+	if (*graph)[node] == nil {
+		(*graph)[node] = make(map[T]bool)
+	}
+}
+
+func (graph *UndirectedGraph[T]) AddEdge(a T, b T) {
+	if a == b {
+		return
+	}
+	graph.AddNode(a)
+	graph.AddNode(b)
+	(*graph)[a][b] = true
+	(*graph)[b][a] = true
+}
+
+func (graph *UndirectedGraph[T]) HasEdge(a T, b T) bool {
+	return (*graph)[a][b]
+}
+
+func (graph *UndirectedGraph[T]) Degree(node T) int {
+	return len((*graph)[node])
+}
+
+// nodesByDegree lists the nodes from most to fewest neighbors.
+func (graph *UndirectedGraph[T]) nodesByDegree() []T {
+	nodes := slices.Collect(maps.Keys(*graph))
+	slices.SortStableFunc(nodes, func(a, b T) int {
+		return cmp.Compare(graph.Degree(b), graph.Degree(a))
+	})
+	return nodes
+}
+
+// Triangles yields every set of three mutually connected nodes, once each.
+func (graph *UndirectedGraph[T]) Triangles() iter.Seq[[3]T] {
+	return func(yield func([3]T) bool) {
+		// only look "forward" in some fixed order so each triangle is seen from its
+		// first node only.
+		nodes := graph.nodesByDegree()
+		rank := make(map[T]int, len(nodes))
+		for i, node := range nodes {
+			rank[node] = i
+		}
+		for _, a := range nodes {
+			for b := range (*graph)[a] {
+				if rank[b] <= rank[a] {
+					continue
+				}
+				for c := range (*graph)[b] {
+					if rank[c] <= rank[b] || !graph.HasEdge(a, c) {
+						continue
+					}
+					if !yield([3]T{a, b, c}) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// MaximalCliques yields every clique that can't be grown by adding another node, using
+// Bron-Kerbosch with pivoting.
+func (graph *UndirectedGraph[T]) MaximalCliques() iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		graph.bronKerbosch(nil, boolSet(maps.Keys(*graph)), make(map[T]bool), func(clique []T) bool {
+			return yield(slices.Clone(clique))
+		})
+	}
+}
+
+// MaximumClique returns a largest set of nodes that are all connected to each other.
+func (graph *UndirectedGraph[T]) MaximumClique() []T {
+	var best []T
+	graph.bronKerbosch(nil, boolSet(maps.Keys(*graph)), make(map[T]bool), func(clique []T) bool {
+		if len(clique) > len(best) {
+			best = slices.Clone(clique)
+		}
+		return true
+	})
+	return best
+}
+
+func boolSet[T comparable](values iter.Seq[T]) map[T]bool {
+	ret := make(map[T]bool)
+	for v := range values {
+		ret[v] = true
+	}
+	return ret
+}
+
+// bronKerbosch reports every maximal clique that contains all of current, some of
+// candidates and none of excluded. report returns false to stop the search.
+func (graph *UndirectedGraph[T]) bronKerbosch(current []T, candidates map[T]bool, excluded map[T]bool, report func([]T) bool) bool {
+	if len(candidates) == 0 && len(excluded) == 0 {
+		return report(current)
+	}
+	// any maximal clique has to include the pivot or one of its non-neighbors, so
+	// there's no need to branch on the pivot's neighbors.
+	var pivot T
+	bestCount := -1
+	for _, set := range []map[T]bool{candidates, excluded} {
+		for u := range set {
+			count := 0
+			for v := range (*graph)[u] {
+				if candidates[v] {
+					count++
+				}
+			}
+			if count > bestCount {
+				pivot, bestCount = u, count
+			}
+		}
+	}
+	toTry := make([]T, 0)
+	for v := range candidates {
+		if !graph.HasEdge(pivot, v) {
+			toTry = append(toTry, v)
+		}
+	}
+	for _, v := range toTry {
+		neighbors := (*graph)[v]
+		nextCandidates := make(map[T]bool)
+		for u := range candidates {
+			if neighbors[u] {
+				nextCandidates[u] = true
+			}
+		}
+		nextExcluded := make(map[T]bool)
+		for u := range excluded {
+			if neighbors[u] {
+				nextExcluded[u] = true
+			}
+		}
+		if !graph.bronKerbosch(append(current, v), nextCandidates, nextExcluded, report) {
+			return false
+		}
+		delete(candidates, v)
+		excluded[v] = true
+	}
+	return true
+}
+
+// GreedyColoring gives each node a color, numbered from 0, so that no two neighbors
+// share one. Nodes are colored most-connected first, each with the lowest color its
+// neighbors haven't taken. That isn't always the fewest colors possible, but it never
+// uses more than one more than the highest degree. It also returns the number of colors.
+func (graph *UndirectedGraph[T]) GreedyColoring() (map[T]int, int) {
+	colors := make(map[T]int)
+	numColors := 0
+	for _, node := range graph.nodesByDegree() {
+		taken := make(map[int]bool)
+		for neighbor := range (*graph)[node] {
+			if c, ok := colors[neighbor]; ok {
+				taken[c] = true
+			}
+		}
+		color := 0
+		for taken[color] {
+			color++
+		}
+		colors[node] = color
+		numColors = max(numColors, color+1)
+	}
+	return colors, numColors
+}
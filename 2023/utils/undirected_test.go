@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// the example network from 2024 day 23.
+var exampleNetwork = strings.Fields(`kh-tc qp-kh de-cg ka-co yn-aq qp-ub cg-tb vc-aq tb-ka wh-tc
+	yn-cg kh-ub ta-co de-co tc-td tb-wq wh-td ta-ka td-qp aq-cg wq-ub ub-vc de-ta wq-aq wq-vc
+	wh-yn ka-de kh-ta co-tc wh-qp tb-vc td-yn`)
+
+func makeExampleNetwork() UndirectedGraph[string] {
+	edges := make([][2]string, 0)
+	for _, line := range exampleNetwork {
+		a, b, _ := strings.Cut(line, "-")
+		edges = append(edges, [2]string{a, b})
+	}
+	return NewUndirectedGraph(edges...)
+}
+
+func TestTriangles(t *testing.T) {
+	graph := makeExampleNetwork()
+	var got []string
+	for triangle := range graph.Triangles() {
+		names := triangle[:]
+		slices.Sort(names)
+		got = append(got, strings.Join(names, ","))
+	}
+	slices.Sort(got)
+	if len(slices.Compact(slices.Clone(got))) != 12 || len(got) != 12 {
+		t.Errorf("Triangles() = %v, want 12 distinct triangles", got)
+	}
+	if !slices.Contains(got, "co,de,ta") || !slices.Contains(got, "aq,cg,yn") {
+		t.Errorf("Triangles() = %v, missing co,de,ta or aq,cg,yn", got)
+	}
+}
+
+func TestMaximumClique(t *testing.T) {
+	graph := makeExampleNetwork()
+	clique := graph.MaximumClique()
+	slices.Sort(clique)
+	if got := strings.Join(clique, ","); got != "co,de,ka,ta" {
+		t.Errorf("MaximumClique() = %v, want co,de,ka,ta", got)
+	}
+
+	numMaximal := 0
+	for clique := range graph.MaximalCliques() {
+		numMaximal++
+		for i, a := range clique {
+			for _, b := range clique[i+1:] {
+				if !graph.HasEdge(a, b) {
+					t.Errorf("MaximalCliques() yielded %v, but %v and %v aren't connected", clique, a, b)
+				}
+			}
+		}
+	}
+	if numMaximal == 0 {
+		t.Error("MaximalCliques() yielded nothing")
+	}
+}
+
+func TestGreedyColoring(t *testing.T) {
+	graph := makeExampleNetwork()
+	colors, numColors := graph.GreedyColoring()
+	if len(colors) != len(graph) {
+		t.Errorf("GreedyColoring() colored %d of %d nodes", len(colors), len(graph))
+	}
+	for node, neighbors := range graph {
+		for neighbor := range neighbors {
+			if colors[node] == colors[neighbor] {
+				t.Errorf("GreedyColoring() gave neighbors %v and %v color %d", node, neighbor, colors[node])
+			}
+		}
+	}
+	// there's a 4-clique, so at least 4 colors are needed.
+	if numColors < 4 {
+		t.Errorf("GreedyColoring() used %d colors, want at least 4", numColors)
+	}
+
+	// an even cycle only needs two.
+	cycle := NewUndirectedGraph([2]int{0, 1}, [2]int{1, 2}, [2]int{2, 3}, [2]int{3, 0})
+	if _, n := cycle.GreedyColoring(); n != 2 {
+		t.Errorf("GreedyColoring() on a 4-cycle used %d colors, want 2", n)
+	}
+}
+
+func TestUndirectedConversion(t *testing.T) {
+	graph := makeStringGraph("ab", "bc", "cc", "ba")
+	undirected := graph.Undirected()
+	if !undirected.HasEdge("b", "a") || !undirected.HasEdge("c", "b") || undirected.HasEdge("c", "c") {
+		t.Errorf("Undirected() = %v", undirected)
+	}
+	back := undirected.Directed(1)
+	if got := len(back.Edges()); got != 4 {
+		t.Errorf("Directed() has %d edges, want 4", got)
+	}
+}
@@ -0,0 +1,79 @@
+package utils
+
+// UnionFind tracks a set of disjoint groups, merging them in nearly constant time.
+// Values join the structure the first time they're mentioned, in a group by themselves.
+type UnionFind[T comparable] struct {
+	parent map[T]T
+	size   map[T]int
+}
+
+func NewUnionFind[T comparable]() *UnionFind[T] {
+	return &UnionFind[T]{parent: make(map[T]T), size: make(map[T]int)}
+}
+
+// Find returns the representative of value's group.
+func (uf *UnionFind[T]) Find(value T) T {
+	parent, ok := uf.parent[value]
+	if !ok {
+		uf.parent[value] = value
+		uf.size[value] = 1
+		return value
+	}
+	if parent == value {
+		return value
+	}
+	root := uf.Find(parent)
+	uf.parent[value] = root
+	return root
+}
+
+// Union merges the groups of a and b, returning false if they were already together.
+func (uf *UnionFind[T]) Union(a T, b T) bool {
+	rootA, rootB := uf.Find(a), uf.Find(b)
+	if rootA == rootB {
+		return false
+	}
+	if uf.size[rootA] < uf.size[rootB] {
+		rootA, rootB = rootB, rootA
+	}
+	uf.parent[rootB] = rootA
+	uf.size[rootA] += uf.size[rootB]
+	delete(uf.size, rootB)
+	return true
+}
+
+func (uf *UnionFind[T]) Connected(a T, b T) bool {
+	return uf.Find(a) == uf.Find(b)
+}
+
+// Size returns how many values are in value's group.
+func (uf *UnionFind[T]) Size(value T) int {
+	return uf.size[uf.Find(value)]
+}
+
+// Groups returns every group, each as a list of its members.
+func (uf *UnionFind[T]) Groups() [][]T {
+	byRoot := make(map[T][]T)
+	for value := range uf.parent {
+		root := uf.Find(value)
+		byRoot[root] = append(byRoot[root], value)
+	}
+	ret := make([][]T, 0, len(byRoot))
+	for _, group := range byRoot {
+		ret = append(ret, group)
+	}
+	return ret
+}
+
+// ConnectedComponents splits the graph into groups of nodes joined by edges, ignoring
+// which way the edges point.
+func (graph *Graph[T]) ConnectedComponents() [][]T {
+	uf := NewUnionFind[T]()
+	for from, tos := range *graph {
+		uf.Find(from)
+		for _, to := range tos {
+			uf.Union(from, to.Value)
+		}
+	}
+	return uf.Groups()
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nsanch/aoc/aoc2023/day19"
+)
+
+// lintCommand checks a file of 2023 day 19 workflows and parts, printing the warnings
+// about the workflows and a table of the regions they accept.
+func lintCommand(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	positional, err := parseInterleaved(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected <file>, got %q", positional)
+	}
+	return day19.Lint(positional[0], os.Stdout)
+}
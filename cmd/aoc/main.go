@@ -0,0 +1,88 @@
+// aoc runs any registered Advent of Code puzzle from a single entry point:
+//
+//	aoc run 2023 17 --part 2 --input path/to/input.txt
+//
+// and checks days against the answers recorded in their dayN-answers.txt files:
+//
+//	aoc verify [--examples] [year [day...]]
+//
+// and lints a file of 2023 day 19 workflows, printing what looks wrong with them and
+// which parts they accept:
+//
+//	aoc lint path/to/workflows.txt
+//
+// Inputs and answers are found through each day's source directory as recorded at build
+// time, so a binary built with -trimpath or copied to another machine only finds them
+// relative to the working directory; pass --input with a full path in that case.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage:")
+	fmt.Fprintln(os.Stderr, "  aoc run <year> <day> [--part 1|2] [--input file] [--cpuprofile file]")
+	fmt.Fprintln(os.Stderr, "  aoc verify [--examples] [year [day...]]")
+	fmt.Fprintln(os.Stderr, "  aoc lint <file>")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "run":
+		err = runCommand(os.Args[2:])
+	case "verify":
+		err = verifyCommand(os.Args[2:])
+	case "lint":
+		err = lintCommand(os.Args[2:])
+	case "help", "-h", "--help":
+		usage()
+		return
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "aoc:", err)
+		os.Exit(1)
+	}
+}
+
+// parseInterleaved lets flags appear before, between or after the positional
+// arguments, since the flag package stops at the first non-flag argument.
+func parseInterleaved(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func parseYearAndDay(positional []string) (int, int, error) {
+	if len(positional) != 2 {
+		return 0, 0, fmt.Errorf("expected <year> <day>, got %q", positional)
+	}
+	year, err := strconv.Atoi(positional[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid year %q: %w", positional[0], err)
+	}
+	day, err := strconv.Atoi(positional[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid day %q: %w", positional[1], err)
+	}
+	return year, day, nil
+}
@@ -0,0 +1,24 @@
+package main
+
+// Each day registers its parts with utils.RegisterPuzzle when imported.
+import (
+	_ "github.com/nsanch/aoc/aoc2023/day1"
+	_ "github.com/nsanch/aoc/aoc2023/day10"
+	_ "github.com/nsanch/aoc/aoc2023/day11"
+	_ "github.com/nsanch/aoc/aoc2023/day12"
+	_ "github.com/nsanch/aoc/aoc2023/day13"
+	_ "github.com/nsanch/aoc/aoc2023/day14"
+	_ "github.com/nsanch/aoc/aoc2023/day15"
+	_ "github.com/nsanch/aoc/aoc2023/day16"
+	_ "github.com/nsanch/aoc/aoc2023/day17"
+	_ "github.com/nsanch/aoc/aoc2023/day18"
+	_ "github.com/nsanch/aoc/aoc2023/day19"
+	_ "github.com/nsanch/aoc/aoc2023/day2"
+	_ "github.com/nsanch/aoc/aoc2023/day3"
+	_ "github.com/nsanch/aoc/aoc2023/day4"
+	_ "github.com/nsanch/aoc/aoc2023/day5"
+	_ "github.com/nsanch/aoc/aoc2023/day6"
+	_ "github.com/nsanch/aoc/aoc2023/day7"
+	_ "github.com/nsanch/aoc/aoc2023/day8"
+	_ "github.com/nsanch/aoc/aoc2023/day9"
+)
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime/pprof"
+
+	"github.com/nsanch/aoc/aoc2023/utils"
+)
+
+func defaultInput(day int) string {
+	return fmt.Sprintf("day%d-input.txt", day)
+}
+
+func runCommand(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	part := fs.Int("part", 0, "part to run (1 or 2); both when omitted")
+	input := fs.String("input", "", "input file; defaults to the day's dayN-input.txt")
+	cpuprofile := fs.String("cpuprofile", "", "write cpu profile to file")
+	positional, err := parseInterleaved(fs, args)
+	if err != nil {
+		return err
+	}
+	year, day, err := parseYearAndDay(positional)
+	if err != nil {
+		return err
+	}
+
+	puzzle, ok := utils.LookupPuzzle(year, day)
+	if !ok {
+		return fmt.Errorf("no puzzle registered for %d day %d", year, day)
+	}
+	parts := []int{1, 2}
+	if *part != 0 {
+		parts = []int{*part}
+	}
+	fname := *input
+	if fname == "" {
+		fname = defaultInput(day)
+	}
+	fname = puzzle.ResolveInput(fname)
+
+	if *cpuprofile != "" {
+		f, err := os.Create(*cpuprofile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return err
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	for _, p := range parts {
+		partFunc, ok := puzzle.Part(p)
+		if !ok {
+			return fmt.Errorf("%d day %d has no part %d", year, day, p)
+		}
+		fmt.Println(partFunc(fname))
+	}
+	return nil
+}
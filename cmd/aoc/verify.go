@@ -0,0 +1,146 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nsanch/aoc/aoc2023/utils"
+)
+
+type verifyStatus string
+
+const (
+	statusPass     verifyStatus = "PASS"
+	statusMismatch verifyStatus = "MISMATCH"
+	statusFail     verifyStatus = "FAIL"
+)
+
+type verifyResult struct {
+	puzzle  *utils.Puzzle
+	answer  utils.Answer
+	status  verifyStatus
+	got     int
+	err     error
+	elapsed time.Duration
+}
+
+func (r verifyResult) String() string {
+	prefix := fmt.Sprintf("%-8s %d day %2d part %d %-24s", r.status, r.puzzle.Year, r.answer.Day, r.answer.Part, r.answer.Input)
+	switch r.status {
+	case statusPass:
+		return fmt.Sprintf("%s = %d (%v)", prefix, r.got, r.elapsed.Round(time.Millisecond))
+	case statusMismatch:
+		return fmt.Sprintf("%s got %d, want %d (%v)", prefix, r.got, r.answer.Expected, r.elapsed.Round(time.Millisecond))
+	}
+	return fmt.Sprintf("%s %v", prefix, r.err)
+}
+
+// callPart turns a panic inside a day's code into an error so one broken day
+// doesn't stop the rest from being checked.
+func callPart(partFunc utils.PartFunc, fname string) (got int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return partFunc(fname), nil
+}
+
+func verifyAnswer(puzzle *utils.Puzzle, answer utils.Answer) verifyResult {
+	result := verifyResult{puzzle: puzzle, answer: answer, status: statusFail}
+	partFunc, ok := puzzle.Part(answer.Part)
+	if !ok {
+		result.err = fmt.Errorf("no part %d registered", answer.Part)
+		return result
+	}
+	fname := puzzle.ResolveInput(answer.Input)
+	if _, err := os.Stat(fname); err != nil {
+		result.err = err
+		return result
+	}
+	start := time.Now()
+	result.got, result.err = callPart(partFunc, fname)
+	result.elapsed = time.Since(start)
+	switch {
+	case result.err != nil:
+		result.status = statusFail
+	case result.got == answer.Expected:
+		result.status = statusPass
+	default:
+		result.status = statusMismatch
+	}
+	return result
+}
+
+func selectPuzzles(positional []string) ([]*utils.Puzzle, error) {
+	if len(positional) == 0 {
+		return utils.AllPuzzles(), nil
+	}
+	year, err := strconv.Atoi(positional[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid year %q: %w", positional[0], err)
+	}
+	ret := make([]*utils.Puzzle, 0)
+	if len(positional) == 1 {
+		for _, p := range utils.AllPuzzles() {
+			if p.Year == year {
+				ret = append(ret, p)
+			}
+		}
+		return ret, nil
+	}
+	for _, dayStr := range positional[1:] {
+		day, err := strconv.Atoi(dayStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid day %q: %w", dayStr, err)
+		}
+		p, ok := utils.LookupPuzzle(year, day)
+		if !ok {
+			return nil, fmt.Errorf("no puzzle registered for %d day %d", year, day)
+		}
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
+
+func verifyCommand(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	examplesOnly := fs.Bool("examples", false, "only check the example (-easy) inputs")
+	positional, err := parseInterleaved(fs, args)
+	if err != nil {
+		return err
+	}
+	puzzles, err := selectPuzzles(positional)
+	if err != nil {
+		return err
+	}
+
+	numChecked := 0
+	numBad := 0
+	for _, puzzle := range puzzles {
+		answers, err := puzzle.Answers()
+		if err != nil {
+			return err
+		}
+		for _, answer := range answers {
+			if *examplesOnly && !strings.Contains(answer.Input, "-easy") {
+				continue
+			}
+			result := verifyAnswer(puzzle, answer)
+			fmt.Println(result.String())
+			numChecked++
+			if result.status != statusPass {
+				numBad++
+			}
+		}
+	}
+	if numBad > 0 {
+		return fmt.Errorf("%d of %d answers did not pass", numBad, numChecked)
+	}
+	fmt.Printf("all %d answers passed\n", numChecked)
+	return nil
+}
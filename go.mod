@@ -1,3 +1,49 @@
 module github.com/nsanch/aoc
 
 go 1.24.1
+
+require (
+	github.com/nsanch/aoc/aoc2023/utils v0.0.0
+	github.com/nsanch/aoc/aoc2023/day1 v0.0.0
+	github.com/nsanch/aoc/aoc2023/day2 v0.0.0
+	github.com/nsanch/aoc/aoc2023/day3 v0.0.0
+	github.com/nsanch/aoc/aoc2023/day4 v0.0.0
+	github.com/nsanch/aoc/aoc2023/day5 v0.0.0
+	github.com/nsanch/aoc/aoc2023/day6 v0.0.0
+	github.com/nsanch/aoc/aoc2023/day7 v0.0.0
+	github.com/nsanch/aoc/aoc2023/day8 v0.0.0
+	github.com/nsanch/aoc/aoc2023/day9 v0.0.0
+	github.com/nsanch/aoc/aoc2023/day10 v0.0.0
+	github.com/nsanch/aoc/aoc2023/day11 v0.0.0
+	github.com/nsanch/aoc/aoc2023/day12 v0.0.0
+	github.com/nsanch/aoc/aoc2023/day13 v0.0.0
+	github.com/nsanch/aoc/aoc2023/day14 v0.0.0
+	github.com/nsanch/aoc/aoc2023/day15 v0.0.0
+	github.com/nsanch/aoc/aoc2023/day16 v0.0.0
+	github.com/nsanch/aoc/aoc2023/day17 v0.0.0
+	github.com/nsanch/aoc/aoc2023/day18 v0.0.0
+	github.com/nsanch/aoc/aoc2023/day19 v0.0.0
+)
+
+replace (
+	github.com/nsanch/aoc/aoc2023/utils => ./2023/utils
+	github.com/nsanch/aoc/aoc2023/day1 => ./2023/day1
+	github.com/nsanch/aoc/aoc2023/day2 => ./2023/day2
+	github.com/nsanch/aoc/aoc2023/day3 => ./2023/day3
+	github.com/nsanch/aoc/aoc2023/day4 => ./2023/day4
+	github.com/nsanch/aoc/aoc2023/day5 => ./2023/day5
+	github.com/nsanch/aoc/aoc2023/day6 => ./2023/day6
+	github.com/nsanch/aoc/aoc2023/day7 => ./2023/day7
+	github.com/nsanch/aoc/aoc2023/day8 => ./2023/day8
+	github.com/nsanch/aoc/aoc2023/day9 => ./2023/day9
+	github.com/nsanch/aoc/aoc2023/day10 => ./2023/day10
+	github.com/nsanch/aoc/aoc2023/day11 => ./2023/day11
+	github.com/nsanch/aoc/aoc2023/day12 => ./2023/day12
+	github.com/nsanch/aoc/aoc2023/day13 => ./2023/day13
+	github.com/nsanch/aoc/aoc2023/day14 => ./2023/day14
+	github.com/nsanch/aoc/aoc2023/day15 => ./2023/day15
+	github.com/nsanch/aoc/aoc2023/day16 => ./2023/day16
+	github.com/nsanch/aoc/aoc2023/day17 => ./2023/day17
+	github.com/nsanch/aoc/aoc2023/day18 => ./2023/day18
+	github.com/nsanch/aoc/aoc2023/day19 => ./2023/day19
+)